@@ -0,0 +1,101 @@
+package alert
+
+import (
+	"errors"
+)
+
+// Type is the type of the alert, which determines the provider used to send it
+type Type string
+
+const (
+	// TypeWebhook is the Type for the generic webhook provider
+	TypeWebhook Type = "webhook"
+
+	// TypeSlack is the Type for the Slack-compatible webhook provider
+	TypeSlack Type = "slack"
+
+	// TypeDingTalk is the Type for the DingTalk robot provider
+	TypeDingTalk Type = "dingtalk"
+
+	// TypeWeCom is the Type for the WeCom robot provider
+	TypeWeCom Type = "wecom"
+
+	// TypeFeishu is the Type for the Feishu robot provider
+	TypeFeishu Type = "feishu"
+)
+
+const (
+	// DefaultFailureThreshold is the default number of failures in a row needed to trigger an alert
+	DefaultFailureThreshold = 3
+
+	// DefaultSuccessThreshold is the default number of successes in a row needed to resolve an alert
+	DefaultSuccessThreshold = 2
+)
+
+var (
+	// ErrAlertWithInvalidType is the error with which Gatus will panic if an alert has an unknown type
+	ErrAlertWithInvalidType = errors.New("invalid alert type")
+
+	// ErrAlertWithInvalidThreshold is the error with which Gatus will panic if an alert has a negative threshold
+	ErrAlertWithInvalidThreshold = errors.New("alert thresholds must not be negative")
+)
+
+// Alert is an endpoint's alert configuration
+type Alert struct {
+	// Type of alert (required)
+	Type Type `yaml:"type"`
+
+	// Enabled defines whether the alert is enabled
+	Enabled *bool `yaml:"enabled,omitempty"`
+
+	// Description of the alert. Will be included in the alert sent.
+	Description string `yaml:"description,omitempty"`
+
+	// FailureThreshold is the number of failures in a row needed before triggering the alert
+	FailureThreshold int `yaml:"failure-threshold,omitempty"`
+
+	// SuccessThreshold defines how many successful executions must happen in a row before an ongoing incident is marked as resolved
+	SuccessThreshold int `yaml:"success-threshold,omitempty"`
+
+	// SendOnResolved defines whether to send a second notification when the issue has been resolved
+	SendOnResolved *bool `yaml:"send-on-resolved,omitempty"`
+
+	// Triggered is used to determine whether an alert has been triggered. When an alert is resolved, this value
+	// should be set back to false. It is used to prevent the same alert from going out twice.
+	Triggered bool `yaml:"-"`
+}
+
+// ValidateAndSetDefaults validates the alert's configuration and sets the default value of args that have one
+func (alert *Alert) ValidateAndSetDefaults() error {
+	switch alert.Type {
+	case TypeWebhook, TypeSlack, TypeDingTalk, TypeWeCom, TypeFeishu:
+	default:
+		return ErrAlertWithInvalidType
+	}
+	if alert.FailureThreshold < 0 || alert.SuccessThreshold < 0 {
+		return ErrAlertWithInvalidThreshold
+	}
+	if alert.FailureThreshold == 0 {
+		alert.FailureThreshold = DefaultFailureThreshold
+	}
+	if alert.SuccessThreshold == 0 {
+		alert.SuccessThreshold = DefaultSuccessThreshold
+	}
+	return nil
+}
+
+// IsEnabled returns whether an alert is enabled or not
+func (alert *Alert) IsEnabled() bool {
+	if alert.Enabled == nil {
+		return true
+	}
+	return *alert.Enabled
+}
+
+// IsSendingOnResolved returns whether an alert is sending on resolve or not
+func (alert *Alert) IsSendingOnResolved() bool {
+	if alert.SendOnResolved == nil {
+		return false
+	}
+	return *alert.SendOnResolved
+}
@@ -0,0 +1,91 @@
+package alerting
+
+import (
+	"log"
+	"sync"
+
+	"github.com/serverless-aliyun/func-status/client/core"
+)
+
+var (
+	mutex sync.Mutex
+
+	// endpointMutexes serializes the handling of the alerts of each endpoint, so that a slow provider only delays the
+	// alerts of the endpoint being sent rather than those of every endpoint
+	endpointMutexes = make(map[string]*sync.Mutex)
+)
+
+// HandleAlerting takes care of alerts to resolve and alerts to trigger based on result success or failure
+func HandleAlerting(endpoint *core.Endpoint, result *core.Result, config *Config) {
+	if config == nil {
+		return
+	}
+	endpointMutex := getEndpointMutex(endpoint)
+	endpointMutex.Lock()
+	defer endpointMutex.Unlock()
+	if result.Success {
+		handleAlertsToResolve(endpoint, result, config)
+	} else {
+		handleAlertsToTrigger(endpoint, result, config)
+	}
+}
+
+// getEndpointMutex returns the mutex guarding the alerting state of the endpoint
+func getEndpointMutex(endpoint *core.Endpoint) *sync.Mutex {
+	key := endpoint.Key()
+	mutex.Lock()
+	defer mutex.Unlock()
+	endpointMutex, exists := endpointMutexes[key]
+	if !exists {
+		endpointMutex = &sync.Mutex{}
+		endpointMutexes[key] = endpointMutex
+	}
+	return endpointMutex
+}
+
+func handleAlertsToTrigger(endpoint *core.Endpoint, result *core.Result, config *Config) {
+	endpoint.NumberOfSuccessesInARow = 0
+	endpoint.NumberOfFailuresInARow++
+	for _, endpointAlert := range endpoint.Alerts {
+		// If the alert hasn't been triggered, move to the next one
+		if !endpointAlert.IsEnabled() || endpointAlert.FailureThreshold > endpoint.NumberOfFailuresInARow {
+			continue
+		}
+		if endpointAlert.Triggered {
+			continue
+		}
+		alertProvider := config.GetAlertingProviderByAlertType(endpointAlert.Type)
+		if alertProvider == nil || !alertProvider.IsValid() {
+			log.Printf("[alerting][handleAlertsToTrigger] Not sending alert of type=%s for endpoint=%s because the provider wasn't configured properly", endpointAlert.Type, endpoint.Key())
+			continue
+		}
+		if err := alertProvider.Send(endpoint, endpointAlert, result, false); err != nil {
+			log.Printf("[alerting][handleAlertsToTrigger] Failed to send an alert for endpoint=%s: %s", endpoint.Key(), err.Error())
+		} else {
+			endpointAlert.Triggered = true
+		}
+	}
+}
+
+func handleAlertsToResolve(endpoint *core.Endpoint, result *core.Result, config *Config) {
+	endpoint.NumberOfSuccessesInARow++
+	for _, endpointAlert := range endpoint.Alerts {
+		if !endpointAlert.IsEnabled() || !endpointAlert.Triggered || endpointAlert.SuccessThreshold > endpoint.NumberOfSuccessesInARow {
+			continue
+		}
+		// Even if the alert provider returns an error, we still set the alert's Triggered variable to false,
+		// otherwise a provider outage would prevent the alert from ever being triggered again.
+		endpointAlert.Triggered = false
+		if !endpointAlert.IsSendingOnResolved() {
+			continue
+		}
+		alertProvider := config.GetAlertingProviderByAlertType(endpointAlert.Type)
+		if alertProvider == nil || !alertProvider.IsValid() {
+			continue
+		}
+		if err := alertProvider.Send(endpoint, endpointAlert, result, true); err != nil {
+			log.Printf("[alerting][handleAlertsToResolve] Failed to send an alert for endpoint=%s: %s", endpoint.Key(), err.Error())
+		}
+	}
+	endpoint.NumberOfFailuresInARow = 0
+}
@@ -0,0 +1,143 @@
+package alerting
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/serverless-aliyun/func-status/client/alerting/alert"
+	"github.com/serverless-aliyun/func-status/client/core"
+)
+
+func TestHandleAlerting(t *testing.T) {
+	var triggered, resolved int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload WebhookPayload
+		decodeJSON(t, r, &payload)
+		if payload.Status == "resolved" {
+			atomic.AddInt32(&resolved, 1)
+		} else {
+			atomic.AddInt32(&triggered, 1)
+		}
+	}))
+	defer server.Close()
+	sendOnResolved := true
+	endpoint := &core.Endpoint{
+		Name:       "website",
+		URL:        "https://example.org",
+		Conditions: []core.Condition{"[STATUS] == 200"},
+		Alerts: []*alert.Alert{
+			{Type: alert.TypeWebhook, FailureThreshold: 2, SuccessThreshold: 2, SendOnResolved: &sendOnResolved},
+		},
+	}
+	if err := endpoint.ValidateAndSetDefaults(); err != nil {
+		t.Fatal("endpoint should've been valid, got", err)
+	}
+	config := &Config{Webhook: &WebhookProvider{URL: server.URL}}
+	scenarios := []struct {
+		success           bool
+		expectedTriggered int32
+		expectedResolved  int32
+		expectedState     bool
+	}{
+		{success: false, expectedTriggered: 0, expectedResolved: 0, expectedState: false},
+		{success: false, expectedTriggered: 1, expectedResolved: 0, expectedState: true},
+		{success: false, expectedTriggered: 1, expectedResolved: 0, expectedState: true},
+		{success: true, expectedTriggered: 1, expectedResolved: 0, expectedState: true},
+		{success: true, expectedTriggered: 1, expectedResolved: 1, expectedState: false},
+		{success: true, expectedTriggered: 1, expectedResolved: 1, expectedState: false},
+		{success: false, expectedTriggered: 1, expectedResolved: 1, expectedState: false},
+		{success: false, expectedTriggered: 2, expectedResolved: 1, expectedState: true},
+	}
+	for i, scenario := range scenarios {
+		HandleAlerting(endpoint, &core.Result{Success: scenario.success}, config)
+		if n := atomic.LoadInt32(&triggered); n != scenario.expectedTriggered {
+			t.Errorf("step %d: expected %d triggered alerts, got %d", i, scenario.expectedTriggered, n)
+		}
+		if n := atomic.LoadInt32(&resolved); n != scenario.expectedResolved {
+			t.Errorf("step %d: expected %d resolved alerts, got %d", i, scenario.expectedResolved, n)
+		}
+		if endpoint.Alerts[0].Triggered != scenario.expectedState {
+			t.Errorf("step %d: expected alert triggered to be %v", i, scenario.expectedState)
+		}
+	}
+}
+
+func TestHandleAlerting_WithoutSendOnResolved(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+	defer server.Close()
+	endpoint := &core.Endpoint{Alerts: []*alert.Alert{{Type: alert.TypeWebhook, FailureThreshold: 1, SuccessThreshold: 1}}}
+	config := &Config{Webhook: &WebhookProvider{URL: server.URL}}
+	HandleAlerting(endpoint, &core.Result{Success: false}, config)
+	HandleAlerting(endpoint, &core.Result{Success: true}, config)
+	if endpoint.Alerts[0].Triggered {
+		t.Error("alert should've been resolved")
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("expected only the triggered alert to be sent, got %d requests", n)
+	}
+}
+
+func TestHandleAlerting_WithProviderError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	endpoint := &core.Endpoint{Alerts: []*alert.Alert{{Type: alert.TypeWebhook, FailureThreshold: 1, SuccessThreshold: 1}}}
+	HandleAlerting(endpoint, &core.Result{Success: false}, &Config{Webhook: &WebhookProvider{URL: server.URL}})
+	if endpoint.Alerts[0].Triggered {
+		t.Error("alert shouldn't have been marked as triggered, because the provider returned an error")
+	}
+}
+
+func TestHandleAlerting_WithoutProvider(t *testing.T) {
+	endpoint := &core.Endpoint{Alerts: []*alert.Alert{{Type: alert.TypeSlack, FailureThreshold: 1, SuccessThreshold: 1}}}
+	HandleAlerting(endpoint, &core.Result{Success: false}, &Config{})
+	if endpoint.Alerts[0].Triggered {
+		t.Error("alert shouldn't have been marked as triggered, because its provider isn't configured")
+	}
+	if endpoint.NumberOfFailuresInARow != 1 {
+		t.Errorf("expected 1 failure in a row, got %d", endpoint.NumberOfFailuresInARow)
+	}
+	HandleAlerting(endpoint, &core.Result{Success: false}, nil)
+	if endpoint.NumberOfFailuresInARow != 1 {
+		t.Error("alerting shouldn't be handled without configuration")
+	}
+}
+
+func TestHandleAlerting_WithSlowProvider(t *testing.T) {
+	sending, release := make(chan struct{}), make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload WebhookPayload
+		decodeJSON(t, r, &payload)
+		if payload.Endpoint == "slow" {
+			close(sending)
+			<-release
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+	config := &Config{Webhook: &WebhookProvider{URL: server.URL}}
+	slow := &core.Endpoint{Name: "slow", Alerts: []*alert.Alert{{Type: alert.TypeWebhook, FailureThreshold: 1, SuccessThreshold: 1}}}
+	fast := &core.Endpoint{Name: "fast", Alerts: []*alert.Alert{{Type: alert.TypeWebhook, FailureThreshold: 1, SuccessThreshold: 1}}}
+	go HandleAlerting(slow, &core.Result{Success: false}, config)
+	<-sending
+	done := make(chan struct{})
+	go func() {
+		HandleAlerting(fast, &core.Result{Success: false}, config)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the alerts of an endpoint shouldn't wait for the provider of another endpoint")
+	}
+	if !fast.Alerts[0].Triggered {
+		t.Error("alert of the fast endpoint should've been triggered")
+	}
+}
@@ -0,0 +1,80 @@
+package alerting
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/serverless-aliyun/func-status/client/alerting/alert"
+	"github.com/serverless-aliyun/func-status/client/core"
+)
+
+// DingTalkProvider is the configuration necessary for sending an alert to a DingTalk robot
+type DingTalkProvider struct {
+	// WebhookURL is the robot's webhook, including its access token
+	WebhookURL string `yaml:"webhook-url"`
+
+	// Secret used to sign the request, if the robot has signing enabled
+	Secret string `yaml:"secret,omitempty"`
+}
+
+// IsValid returns whether the provider's configuration is valid
+func (provider *DingTalkProvider) IsValid() bool {
+	return len(provider.WebhookURL) > 0
+}
+
+// Send an alert using the provider's configuration
+func (provider *DingTalkProvider) Send(endpoint *core.Endpoint, alert *alert.Alert, result *core.Result, resolved bool) error {
+	title, text := buildMessage(endpoint, alert, result, resolved)
+	webhookURL := provider.WebhookURL
+	if len(provider.Secret) > 0 {
+		timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+		separator := "?"
+		if strings.Contains(webhookURL, "?") {
+			separator = "&"
+		}
+		webhookURL += separator + "timestamp=" + timestamp + "&sign=" + url.QueryEscape(signDingTalk(timestamp, provider.Secret))
+	}
+	body, err := post(webhookURL, nil, map[string]interface{}{
+		"msgtype": "markdown",
+		"markdown": map[string]string{
+			"title": title,
+			"text":  strings.ReplaceAll(text, "\n", "\n\n"),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return checkRobotResponse(body)
+}
+
+// signDingTalk computes the signature of a DingTalk robot request
+func signDingTalk(timestamp, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// robotResponse is the response returned by the DingTalk and WeCom robots
+type robotResponse struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+// checkRobotResponse returns an error if the robot didn't accept the message
+func checkRobotResponse(body []byte) error {
+	var response robotResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return err
+	}
+	if response.ErrCode != 0 {
+		return fmt.Errorf("robot returned error code %d: %s", response.ErrCode, response.ErrMsg)
+	}
+	return nil
+}
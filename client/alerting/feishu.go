@@ -0,0 +1,65 @@
+package alerting
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/serverless-aliyun/func-status/client/alerting/alert"
+	"github.com/serverless-aliyun/func-status/client/core"
+)
+
+// FeishuProvider is the configuration necessary for sending an alert to a Feishu custom bot
+type FeishuProvider struct {
+	// WebhookURL is the bot's webhook
+	WebhookURL string `yaml:"webhook-url"`
+
+	// Secret used to sign the request, if the bot has signature verification enabled
+	Secret string `yaml:"secret,omitempty"`
+}
+
+// IsValid returns whether the provider's configuration is valid
+func (provider *FeishuProvider) IsValid() bool {
+	return len(provider.WebhookURL) > 0
+}
+
+// Send an alert using the provider's configuration
+func (provider *FeishuProvider) Send(endpoint *core.Endpoint, alert *alert.Alert, result *core.Result, resolved bool) error {
+	_, text := buildMessage(endpoint, alert, result, resolved)
+	payload := map[string]interface{}{
+		"msg_type": "text",
+		"content": map[string]string{
+			"text": text,
+		},
+	}
+	if len(provider.Secret) > 0 {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		payload["timestamp"] = timestamp
+		payload["sign"] = signFeishu(timestamp, provider.Secret)
+	}
+	body, err := post(provider.WebhookURL, nil, payload)
+	if err != nil {
+		return err
+	}
+	var response struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if err = json.Unmarshal(body, &response); err != nil {
+		return err
+	}
+	if response.Code != 0 {
+		return fmt.Errorf("bot returned error code %d: %s", response.Code, response.Msg)
+	}
+	return nil
+}
+
+// signFeishu computes the signature of a Feishu bot request
+func signFeishu(timestamp, secret string) string {
+	mac := hmac.New(sha256.New, []byte(timestamp+"\n"+secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,132 @@
+package alerting
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/serverless-aliyun/func-status/client/alerting/alert"
+	"github.com/serverless-aliyun/func-status/client/core"
+	"github.com/serverless-aliyun/func-status/client/util"
+)
+
+// AlertProvider is the interface that each provider should implement
+type AlertProvider interface {
+	// IsValid returns whether the provider's configuration is valid
+	IsValid() bool
+
+	// Send an alert using the provider's configuration
+	Send(endpoint *core.Endpoint, alert *alert.Alert, result *core.Result, resolved bool) error
+}
+
+// Config is the configuration for alerting providers
+type Config struct {
+	// Webhook is the configuration for the generic webhook provider
+	Webhook *WebhookProvider `yaml:"webhook,omitempty"`
+
+	// Slack is the configuration for the Slack-compatible webhook provider
+	Slack *SlackProvider `yaml:"slack,omitempty"`
+
+	// DingTalk is the configuration for the DingTalk robot provider
+	DingTalk *DingTalkProvider `yaml:"dingtalk,omitempty"`
+
+	// WeCom is the configuration for the WeCom robot provider
+	WeCom *WeComProvider `yaml:"wecom,omitempty"`
+
+	// Feishu is the configuration for the Feishu robot provider
+	Feishu *FeishuProvider `yaml:"feishu,omitempty"`
+}
+
+// GetAlertingProviderByAlertType returns an AlertProvider by its corresponding alert.Type,
+// or nil if the provider isn't configured
+func (config *Config) GetAlertingProviderByAlertType(alertType alert.Type) AlertProvider {
+	if config == nil {
+		return nil
+	}
+	switch alertType {
+	case alert.TypeWebhook:
+		if config.Webhook != nil {
+			return config.Webhook
+		}
+	case alert.TypeSlack:
+		if config.Slack != nil {
+			return config.Slack
+		}
+	case alert.TypeDingTalk:
+		if config.DingTalk != nil {
+			return config.DingTalk
+		}
+	case alert.TypeWeCom:
+		if config.WeCom != nil {
+			return config.WeCom
+		}
+	case alert.TypeFeishu:
+		if config.Feishu != nil {
+			return config.Feishu
+		}
+	}
+	return nil
+}
+
+// buildMessage returns the title and the text of the message describing the alert
+func buildMessage(endpoint *core.Endpoint, alert *alert.Alert, result *core.Result, resolved bool) (string, string) {
+	var title string
+	if resolved {
+		title = fmt.Sprintf("[RESOLVED] %s", endpoint.DisplayName())
+	} else {
+		title = fmt.Sprintf("[TRIGGERED] %s", endpoint.DisplayName())
+	}
+	text := new(strings.Builder)
+	text.WriteString(title)
+	if resolved {
+		_, _ = fmt.Fprintf(text, "\nAlert has been resolved after passing successfully %d time(s) in a row", alert.SuccessThreshold)
+	} else {
+		_, _ = fmt.Fprintf(text, "\nAlert has been triggered due to having failed %d time(s) in a row", alert.FailureThreshold)
+	}
+	if len(alert.Description) > 0 {
+		text.WriteString("\nDescription: " + alert.Description)
+	}
+	for _, conditionResult := range result.ConditionResults {
+		if conditionResult.Success {
+			text.WriteString("\n✅ " + conditionResult.Condition)
+		} else {
+			text.WriteString("\n❌ " + conditionResult.Condition)
+		}
+	}
+	for _, resultError := range result.Errors {
+		text.WriteString("\n⚠️ " + resultError)
+	}
+	return title, text.String()
+}
+
+// post sends the payload as JSON to the given url and returns the body of the response
+func post(url string, headers map[string]string, payload interface{}) ([]byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set(core.ContentTypeHeader, "application/json")
+	for k, v := range headers {
+		request.Header.Set(k, v)
+	}
+	response, err := util.GetHTTPClient().Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("call to provider alert returned status code %d: %s", response.StatusCode, string(responseBody))
+	}
+	return responseBody, nil
+}
@@ -0,0 +1,188 @@
+package alerting
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/serverless-aliyun/func-status/client/alerting/alert"
+	"github.com/serverless-aliyun/func-status/client/core"
+)
+
+func decodeJSON(t *testing.T, r *http.Request, v interface{}) {
+	t.Helper()
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		t.Error("failed to decode request body:", err)
+	}
+}
+
+var (
+	testEndpoint = &core.Endpoint{Name: "website", URL: "https://example.org"}
+	testAlert    = &alert.Alert{Type: alert.TypeWebhook, Description: "healthcheck failed", FailureThreshold: 3, SuccessThreshold: 2}
+	testResult   = &core.Result{
+		ConditionResults: []*core.ConditionResult{
+			{Condition: "[CONNECTED] == true", Success: true},
+			{Condition: "[STATUS] (500) == 200", Success: false},
+		},
+		Errors: []string{"connection reset"},
+	}
+)
+
+func TestConfig_GetAlertingProviderByAlertType(t *testing.T) {
+	config := &Config{Slack: &SlackProvider{WebhookURL: "http://example.org"}}
+	if provider := config.GetAlertingProviderByAlertType(alert.TypeSlack); provider == nil {
+		t.Error("expected slack provider to be returned")
+	}
+	if provider := config.GetAlertingProviderByAlertType(alert.TypeDingTalk); provider != nil {
+		t.Error("expected no provider to be returned because dingtalk isn't configured")
+	}
+	var nilConfig *Config
+	if provider := nilConfig.GetAlertingProviderByAlertType(alert.TypeSlack); provider != nil {
+		t.Error("expected no provider to be returned from a nil configuration")
+	}
+}
+
+func TestBuildMessage(t *testing.T) {
+	title, text := buildMessage(testEndpoint, testAlert, testResult, false)
+	if title != "[TRIGGERED] website" {
+		t.Errorf("unexpected title %s", title)
+	}
+	for _, expected := range []string{"failed 3 time(s) in a row", "Description: healthcheck failed", "✅ [CONNECTED] == true", "❌ [STATUS] (500) == 200", "⚠️ connection reset"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("expected text to contain %q, got %q", expected, text)
+		}
+	}
+	title, text = buildMessage(testEndpoint, testAlert, testResult, true)
+	if title != "[RESOLVED] website" || !strings.Contains(text, "passing successfully 2 time(s) in a row") {
+		t.Errorf("unexpected resolved message %q: %q", title, text)
+	}
+}
+
+func TestWebhookProvider_Send(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Error("expected the configured headers to be sent")
+		}
+		var payload WebhookPayload
+		decodeJSON(t, r, &payload)
+		if payload.Key != "website" || payload.Status != "resolved" || len(payload.ConditionResults) != 2 {
+			t.Errorf("unexpected payload %+v", payload)
+		}
+	}))
+	defer server.Close()
+	provider := &WebhookProvider{URL: server.URL, Headers: map[string]string{"Authorization": "Bearer token"}}
+	if !provider.IsValid() {
+		t.Error("provider should've been valid")
+	}
+	if err := provider.Send(testEndpoint, testAlert, testResult, true); err != nil {
+		t.Error("expected no error, got", err)
+	}
+	if (&WebhookProvider{}).IsValid() {
+		t.Error("provider without url shouldn't have been valid")
+	}
+}
+
+func TestSlackProvider_Send(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		decodeJSON(t, r, &payload)
+		if !strings.HasPrefix(payload["text"], "[TRIGGERED] website") {
+			t.Errorf("unexpected payload %+v", payload)
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	if err := (&SlackProvider{WebhookURL: server.URL}).Send(testEndpoint, testAlert, testResult, false); err != nil {
+		t.Error("expected no error, got", err)
+	}
+}
+
+func TestDingTalkProvider_Send(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timestamp := r.URL.Query().Get("timestamp")
+		if r.URL.Query().Get("access_token") != "abc" || r.URL.Query().Get("sign") != signDingTalk(timestamp, "SEC") {
+			_, _ = w.Write([]byte(`{"errcode":310000,"errmsg":"sign not match"}`))
+			return
+		}
+		var payload struct {
+			MsgType  string            `json:"msgtype"`
+			Markdown map[string]string `json:"markdown"`
+		}
+		decodeJSON(t, r, &payload)
+		if payload.MsgType != "markdown" || payload.Markdown["title"] != "[TRIGGERED] website" {
+			t.Errorf("unexpected payload %+v", payload)
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer server.Close()
+	if err := (&DingTalkProvider{WebhookURL: server.URL + "?access_token=abc", Secret: "SEC"}).Send(testEndpoint, testAlert, testResult, false); err != nil {
+		t.Error("expected no error, got", err)
+	}
+	if err := (&DingTalkProvider{WebhookURL: server.URL + "?access_token=abc", Secret: "WRONG"}).Send(testEndpoint, testAlert, testResult, false); err == nil {
+		t.Error("expected an error because the signature is wrong")
+	}
+}
+
+func TestWeComProvider_Send(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			MsgType  string            `json:"msgtype"`
+			Markdown map[string]string `json:"markdown"`
+		}
+		decodeJSON(t, r, &payload)
+		if payload.MsgType != "markdown" || !strings.Contains(payload.Markdown["content"], "website") {
+			t.Errorf("unexpected payload %+v", payload)
+		}
+		if r.URL.Query().Get("key") != "abc" {
+			_, _ = w.Write([]byte(`{"errcode":93000,"errmsg":"invalid webhook url"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer server.Close()
+	if err := (&WeComProvider{WebhookURL: server.URL + "?key=abc"}).Send(testEndpoint, testAlert, testResult, false); err != nil {
+		t.Error("expected no error, got", err)
+	}
+	if err := (&WeComProvider{WebhookURL: server.URL}).Send(testEndpoint, testAlert, testResult, false); err == nil {
+		t.Error("expected an error because the robot returned an error code")
+	}
+}
+
+func TestFeishuProvider_Send(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Timestamp string            `json:"timestamp"`
+			Sign      string            `json:"sign"`
+			MsgType   string            `json:"msg_type"`
+			Content   map[string]string `json:"content"`
+		}
+		decodeJSON(t, r, &payload)
+		if payload.Sign != signFeishu(payload.Timestamp, "SEC") {
+			_, _ = w.Write([]byte(`{"code":19021,"msg":"sign match fail or timestamp is not within one hour from current time"}`))
+			return
+		}
+		if payload.MsgType != "text" || !strings.HasPrefix(payload.Content["text"], "[RESOLVED] website") {
+			t.Errorf("unexpected payload %+v", payload)
+		}
+		_, _ = w.Write([]byte(`{"code":0,"msg":"success"}`))
+	}))
+	defer server.Close()
+	if err := (&FeishuProvider{WebhookURL: server.URL, Secret: "SEC"}).Send(testEndpoint, testAlert, testResult, true); err != nil {
+		t.Error("expected no error, got", err)
+	}
+	if err := (&FeishuProvider{WebhookURL: server.URL}).Send(testEndpoint, testAlert, testResult, true); err == nil {
+		t.Error("expected an error because the request wasn't signed")
+	}
+}
+
+func TestPost_WithErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+	if _, err := post(server.URL, nil, map[string]string{}); err == nil {
+		t.Error("expected an error because the server returned 400")
+	}
+}
@@ -0,0 +1,24 @@
+package alerting
+
+import (
+	"github.com/serverless-aliyun/func-status/client/alerting/alert"
+	"github.com/serverless-aliyun/func-status/client/core"
+)
+
+// SlackProvider is the configuration necessary for sending an alert to a Slack-compatible incoming webhook
+type SlackProvider struct {
+	// WebhookURL is the incoming webhook the alert is posted to
+	WebhookURL string `yaml:"webhook-url"`
+}
+
+// IsValid returns whether the provider's configuration is valid
+func (provider *SlackProvider) IsValid() bool {
+	return len(provider.WebhookURL) > 0
+}
+
+// Send an alert using the provider's configuration
+func (provider *SlackProvider) Send(endpoint *core.Endpoint, alert *alert.Alert, result *core.Result, resolved bool) error {
+	_, text := buildMessage(endpoint, alert, result, resolved)
+	_, err := post(provider.WebhookURL, nil, map[string]string{"text": text})
+	return err
+}
@@ -0,0 +1,53 @@
+package alerting
+
+import (
+	"time"
+
+	"github.com/serverless-aliyun/func-status/client/alerting/alert"
+	"github.com/serverless-aliyun/func-status/client/core"
+)
+
+// WebhookProvider is the configuration necessary for sending an alert to a generic webhook
+type WebhookProvider struct {
+	// URL the alert is posted to
+	URL string `yaml:"url"`
+
+	// Headers added to the request
+	Headers map[string]string `yaml:"headers,omitempty"`
+}
+
+// WebhookPayload is the JSON body posted to the webhook
+type WebhookPayload struct {
+	Endpoint         string                  `json:"endpoint"`
+	Key              string                  `json:"key"`
+	URL              string                  `json:"url"`
+	Status           string                  `json:"status"`
+	Description      string                  `json:"description,omitempty"`
+	ConditionResults []*core.ConditionResult `json:"conditionResults"`
+	Errors           []string                `json:"errors,omitempty"`
+	Timestamp        time.Time               `json:"timestamp"`
+}
+
+// IsValid returns whether the provider's configuration is valid
+func (provider *WebhookProvider) IsValid() bool {
+	return len(provider.URL) > 0
+}
+
+// Send an alert using the provider's configuration
+func (provider *WebhookProvider) Send(endpoint *core.Endpoint, alert *alert.Alert, result *core.Result, resolved bool) error {
+	status := "triggered"
+	if resolved {
+		status = "resolved"
+	}
+	_, err := post(provider.URL, provider.Headers, &WebhookPayload{
+		Endpoint:         endpoint.DisplayName(),
+		Key:              endpoint.Key(),
+		URL:              endpoint.URL,
+		Status:           status,
+		Description:      alert.Description,
+		ConditionResults: result.ConditionResults,
+		Errors:           result.Errors,
+		Timestamp:        result.Timestamp,
+	})
+	return err
+}
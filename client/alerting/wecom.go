@@ -0,0 +1,32 @@
+package alerting
+
+import (
+	"github.com/serverless-aliyun/func-status/client/alerting/alert"
+	"github.com/serverless-aliyun/func-status/client/core"
+)
+
+// WeComProvider is the configuration necessary for sending an alert to a WeCom group robot
+type WeComProvider struct {
+	// WebhookURL is the robot's webhook, including its key
+	WebhookURL string `yaml:"webhook-url"`
+}
+
+// IsValid returns whether the provider's configuration is valid
+func (provider *WeComProvider) IsValid() bool {
+	return len(provider.WebhookURL) > 0
+}
+
+// Send an alert using the provider's configuration
+func (provider *WeComProvider) Send(endpoint *core.Endpoint, alert *alert.Alert, result *core.Result, resolved bool) error {
+	_, text := buildMessage(endpoint, alert, result, resolved)
+	body, err := post(provider.WebhookURL, nil, map[string]interface{}{
+		"msgtype": "markdown",
+		"markdown": map[string]string{
+			"content": text,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return checkRobotResponse(body)
+}
@@ -0,0 +1,186 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/serverless-aliyun/func-status/client/storage"
+)
+
+const (
+	// DefaultPageSize is the number of items returned when no page size is requested
+	DefaultPageSize = 20
+
+	// MaximumPageSize is the maximum number of items that can be requested at once
+	MaximumPageSize = 100
+
+	// MaximumPage is the maximum page that can be requested, so that the offset of the page can't overflow
+	MaximumPage = 100000
+
+	// DefaultDays is the number of days returned when no range is requested
+	DefaultDays = 30
+
+	// MaximumDays is the maximum number of days that can be requested at once
+	MaximumDays = 365
+)
+
+var (
+	// ErrMissingKey is the error returned when a request requires an endpoint key but none was provided
+	ErrMissingKey = errors.New("missing query parameter: key")
+
+	// ErrInvalidParameter is the error returned when a numerical query parameter couldn't be parsed
+	ErrInvalidParameter = errors.New("invalid query parameter")
+)
+
+// Page is a paginated list of items
+type Page[T any] struct {
+	Page     int   `json:"page"`
+	PageSize int   `json:"pageSize"`
+	Total    int64 `json:"total,omitempty"`
+	Items    []T   `json:"items"`
+}
+
+// handler serves the read API from a storage.Store
+type handler struct {
+	store storage.Store
+}
+
+// NewHandler returns the handler serving the read API of the status page
+func NewHandler(store storage.Store) http.Handler {
+	h := &handler{store: store}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/endpoints", h.endpoints)
+	mux.HandleFunc("/api/endpoints/results", h.results)
+	mux.HandleFunc("/api/endpoints/response-times", h.responseTimes)
+	mux.HandleFunc("/api/endpoints/checks", h.checks)
+	return mux
+}
+
+// endpoints returns a page of endpoints with their SLA and status
+//
+// Usage: GET /api/endpoints?page=1&pageSize=20
+func (h *handler) endpoints(w http.ResponseWriter, r *http.Request) {
+	page, pageSize, err := extractPageAndPageSize(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	items, total, err := h.store.ListEndpoints(page, pageSize)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, &Page[storage.Endpoint]{Page: page, PageSize: pageSize, Total: total, Items: items})
+}
+
+// results returns the daily results of an endpoint
+//
+// Usage: GET /api/endpoints/results?key=website&days=30
+func (h *handler) results(w http.ResponseWriter, r *http.Request) {
+	key := r.URL.Query().Get("key")
+	if len(key) == 0 {
+		writeError(w, http.StatusBadRequest, ErrMissingKey)
+		return
+	}
+	days, err := extractInt(r, "days", DefaultDays, MaximumDays)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	items, err := h.store.ListResults(key, days)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, items)
+}
+
+// responseTimes returns the daily response time distribution of an endpoint
+//
+// Usage: GET /api/endpoints/response-times?key=website&days=30
+func (h *handler) responseTimes(w http.ResponseWriter, r *http.Request) {
+	key := r.URL.Query().Get("key")
+	if len(key) == 0 {
+		writeError(w, http.StatusBadRequest, ErrMissingKey)
+		return
+	}
+	days, err := extractInt(r, "days", DefaultDays, MaximumDays)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	items, err := h.store.ListResponseTimes(key, days)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, items)
+}
+
+// checks returns a page of the latest checks of an endpoint
+//
+// Usage: GET /api/endpoints/checks?key=website&page=1&pageSize=20
+func (h *handler) checks(w http.ResponseWriter, r *http.Request) {
+	key := r.URL.Query().Get("key")
+	if len(key) == 0 {
+		writeError(w, http.StatusBadRequest, ErrMissingKey)
+		return
+	}
+	page, pageSize, err := extractPageAndPageSize(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	items, err := h.store.ListChecks(key, page, pageSize)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, &Page[storage.Check]{Page: page, PageSize: pageSize, Items: items})
+}
+
+// extractPageAndPageSize extracts the page and pageSize query parameters from the request
+func extractPageAndPageSize(r *http.Request) (page, pageSize int, err error) {
+	if page, err = extractInt(r, "page", 1, MaximumPage); err != nil {
+		return 0, 0, err
+	}
+	if pageSize, err = extractInt(r, "pageSize", DefaultPageSize, MaximumPageSize); err != nil {
+		return 0, 0, err
+	}
+	return page, pageSize, nil
+}
+
+// extractInt extracts a positive integer query parameter from the request, capped to maximum if maximum isn't 0
+func extractInt(r *http.Request, name string, defaultValue, maximum int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if len(value) == 0 {
+		return defaultValue, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidParameter, name)
+	}
+	if maximum > 0 && number > maximum {
+		number = maximum
+	}
+	return number, nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("[api][writeJSON] Failed to write response: %s", err.Error())
+	}
+}
+
+func writeError(w http.ResponseWriter, statusCode int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/serverless-aliyun/func-status/client/core"
+	"github.com/serverless-aliyun/func-status/client/storage"
+)
+
+func TestExtractPageAndPageSize(t *testing.T) {
+	scenarios := []struct {
+		query            string
+		expectedPage     int
+		expectedPageSize int
+		expectedErr      bool
+	}{
+		{query: "", expectedPage: 1, expectedPageSize: DefaultPageSize},
+		{query: "page=2&pageSize=5", expectedPage: 2, expectedPageSize: 5},
+		{query: "pageSize=100", expectedPage: 1, expectedPageSize: MaximumPageSize},
+		{query: "pageSize=101", expectedPage: 1, expectedPageSize: MaximumPageSize},
+		{query: "pageSize=1000", expectedPage: 1, expectedPageSize: MaximumPageSize},
+		{query: "page=0", expectedErr: true},
+		{query: "page=100000", expectedPage: MaximumPage, expectedPageSize: DefaultPageSize},
+		{query: "page=100001", expectedPage: MaximumPage, expectedPageSize: DefaultPageSize},
+		{query: "page=4611686018427387904", expectedPage: MaximumPage, expectedPageSize: DefaultPageSize},
+		{query: "page=99999999999999999999", expectedErr: true},
+		{query: "pageSize=abc", expectedErr: true},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.query, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/api/endpoints?"+scenario.query, nil)
+			page, pageSize, err := extractPageAndPageSize(request)
+			if scenario.expectedErr {
+				if !errors.Is(err, ErrInvalidParameter) {
+					t.Errorf("expected error %v, got %v", ErrInvalidParameter, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("expected no error, got", err)
+			}
+			if page != scenario.expectedPage || pageSize != scenario.expectedPageSize {
+				t.Errorf("expected page %d and page size %d, got %d and %d", scenario.expectedPage, scenario.expectedPageSize, page, pageSize)
+			}
+		})
+	}
+}
+
+func TestHandler_WithMissingKey(t *testing.T) {
+	handler := NewHandler(storage.NewMemoryStore())
+	for _, path := range []string{"/api/endpoints/results", "/api/endpoints/checks"} {
+		t.Run(path, func(t *testing.T) {
+			responseRecorder := httptest.NewRecorder()
+			handler.ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodGet, path, nil))
+			if responseRecorder.Code != http.StatusBadRequest {
+				t.Errorf("expected status %d, got %d", http.StatusBadRequest, responseRecorder.Code)
+			}
+		})
+	}
+}
+
+func TestHandler_Endpoints(t *testing.T) {
+	store := storage.NewMemoryStore()
+	for _, name := range []string{"b", "a", "c"} {
+		endpoint := &core.Endpoint{Name: name, URL: "https://example.org"}
+		_ = store.SaveResult(endpoint.Key(), &core.Result{Success: true}, 30, core.SLAModeCondition)
+		_ = store.SaveEndpoint(endpoint)
+	}
+	responseRecorder := httptest.NewRecorder()
+	NewHandler(store).ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodGet, "/api/endpoints?page=2&pageSize=2", nil))
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, responseRecorder.Code)
+	}
+	var page Page[storage.Endpoint]
+	if err := json.Unmarshal(responseRecorder.Body.Bytes(), &page); err != nil {
+		t.Fatal("failed to decode response:", err)
+	}
+	if page.Total != 3 || len(page.Items) != 1 || page.Items[0].Key != "c" {
+		t.Errorf("unexpected page %+v", page)
+	}
+}
+
+func TestHandler_ResultsAndChecks(t *testing.T) {
+	store := storage.NewMemoryStore()
+	_ = store.SaveResult("website", &core.Result{Success: true, ConditionResults: []*core.ConditionResult{{Condition: "[STATUS] == 200", Success: true}}}, 30, core.SLAModeCondition)
+	handler := NewHandler(store)
+	responseRecorder := httptest.NewRecorder()
+	handler.ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodGet, "/api/endpoints/results?key=website&days=7", nil))
+	var results []storage.Result
+	if err := json.Unmarshal(responseRecorder.Body.Bytes(), &results); err != nil {
+		t.Fatal("failed to decode response:", err)
+	}
+	if len(results) != 1 || results[0].Status != storage.StatusSuccess {
+		t.Errorf("unexpected results %+v", results)
+	}
+	responseRecorder = httptest.NewRecorder()
+	handler.ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodGet, "/api/endpoints/checks?key=website", nil))
+	var page Page[storage.Check]
+	if err := json.Unmarshal(responseRecorder.Body.Bytes(), &page); err != nil {
+		t.Fatal("failed to decode response:", err)
+	}
+	if len(page.Items) != 1 || page.Items[0].Conditions[0].Condition != "[STATUS] == 200" {
+		t.Errorf("unexpected checks %+v", page)
+	}
+}
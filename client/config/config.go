@@ -1,29 +1,44 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"github.com/apolloconfig/agollo/v4"
 	"github.com/apolloconfig/agollo/v4/constant"
 	apollo "github.com/apolloconfig/agollo/v4/env/config"
 	"github.com/apolloconfig/agollo/v4/extension"
 	"github.com/chzyer/logex"
+	"github.com/serverless-aliyun/func-status/client/alerting"
 	"github.com/serverless-aliyun/func-status/client/core"
+	"github.com/serverless-aliyun/func-status/client/scheduler"
 	"gopkg.in/yaml.v3"
 	"log"
 	"os"
 )
 
+// ErrNoConfiguration is the error returned when the configuration is empty
+var ErrNoConfiguration = errors.New("configuration is empty")
+
 // Config is the main configuration structure
 type Config struct {
 	// Debug Whether to enable debug logs
 	Debug bool `yaml:"debug,omitempty"`
 
-	// MaxDays of results to keep
+	// MaxDays of results, checks and resolved incidents to keep
 	MaxDays int `yaml:"maxDays,omitempty"`
 
 	// Database DSN
 	DSN string `yaml:"dsn,omitempty"`
 
+	// SLAMode defines how the SLA of endpoints is computed (condition, check or time). Defaults to condition.
+	SLAMode core.SLAMode `yaml:"slaMode,omitempty"`
+
+	// Alerting configuration of the alerting providers
+	Alerting *alerting.Config `yaml:"alerting,omitempty"`
+
+	// Scheduler configuration of the check runner
+	Scheduler *scheduler.Config `yaml:"scheduler,omitempty"`
+
 	// Endpoints List of endpoints to monitor
 	Endpoints []*core.Endpoint `yaml:"endpoints,omitempty"`
 }
@@ -42,8 +57,8 @@ func LoadConfiguration(cfgPath string) (*Config, error) {
 		log.Printf("Error parse configuration from %s: %s", cfgPath, err)
 		return nil, fmt.Errorf("error parse configuration from file %s: %w", cfgPath, err)
 	}
-	if config.MaxDays == 0 {
-		config.MaxDays = 30
+	if err = config.validateAndSetDefaults(); err != nil {
+		return nil, err
 	}
 	return config, err
 }
@@ -68,8 +83,39 @@ func LoadApolloConfiguration() (*Config, error) {
 	remoteConfig := client.GetConfig(c.NamespaceName).GetContent()
 	log.Printf("Success Load Remote Config: %s\n", remoteConfig)
 	var config *Config
-	err := yaml.Unmarshal([]byte(remoteConfig), &config)
-	return config, err
+	if err := yaml.Unmarshal([]byte(remoteConfig), &config); err != nil {
+		return nil, err
+	}
+	if err := config.validateAndSetDefaults(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// validateAndSetDefaults validates the configuration and sets the default value of args that have one
+func (config *Config) validateAndSetDefaults() error {
+	if config == nil {
+		return ErrNoConfiguration
+	}
+	if config.MaxDays == 0 {
+		config.MaxDays = 30
+	}
+	if len(config.SLAMode) == 0 {
+		config.SLAMode = core.SLAModeCondition
+	}
+	if err := config.SLAMode.Validate(); err != nil {
+		return err
+	}
+	for _, endpoint := range config.Endpoints {
+		if len(endpoint.SLAMode) == 0 {
+			endpoint.SLAMode = config.SLAMode
+		}
+		// Validating the endpoint also compiles its conditions and sets the defaults of its alerts
+		if err := endpoint.ValidateAndSetDefaults(); err != nil {
+			return fmt.Errorf("invalid endpoint %s: %w", endpoint.DisplayName(), err)
+		}
+	}
+	return nil
 }
 
 // Parser properties转换器
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/serverless-aliyun/func-status/client/alerting/alert"
+	"github.com/serverless-aliyun/func-status/client/core"
+)
+
+func TestConfig_validateAndSetDefaults(t *testing.T) {
+	endpoint := &core.Endpoint{
+		Name:       "website",
+		URL:        "https://example.org/health",
+		Conditions: []core.Condition{"[STATUS] == 200", "[BODY].status == UP"},
+		Alerts:     []*alert.Alert{{Type: alert.TypeWebhook}},
+	}
+	config := &Config{Endpoints: []*core.Endpoint{endpoint}}
+	if err := config.validateAndSetDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	if config.MaxDays != 30 {
+		t.Errorf("expected MaxDays to default to 30, got %d", config.MaxDays)
+	}
+	if endpoint.SLAMode != core.SLAModeCondition {
+		t.Errorf("expected the SLA mode of the endpoint to default to %s, got %s", core.SLAModeCondition, endpoint.SLAMode)
+	}
+	if endpoint.Headers[core.UserAgentHeader] != core.GatusUserAgent {
+		t.Errorf("expected the endpoint to be validated and have the default user agent, got headers %v", endpoint.Headers)
+	}
+	if endpoint.Alerts[0].FailureThreshold != alert.DefaultFailureThreshold {
+		t.Errorf("expected the failure threshold of the alert to default to %d, got %d", alert.DefaultFailureThreshold, endpoint.Alerts[0].FailureThreshold)
+	}
+}
+
+func TestConfig_validateAndSetDefaultsWithInvalidEndpoint(t *testing.T) {
+	config := &Config{Endpoints: []*core.Endpoint{
+		{Name: "website", URL: "https://example.org/health", Conditions: []core.Condition{"[STATUS] == 200"}},
+		{Name: "api", URL: "https://example.org/api", Conditions: []core.Condition{"[RESPONSE_TIME] < fast"}},
+	}}
+	err := config.validateAndSetDefaults()
+	if err == nil {
+		t.Fatal("expected an error, since the condition of the api endpoint is invalid")
+	}
+	if !strings.HasPrefix(err.Error(), "invalid endpoint api: ") {
+		t.Errorf("expected the error to name the invalid endpoint, got %v", err)
+	}
+}
+
+func TestConfig_validateAndSetDefaultsWithNoConfiguration(t *testing.T) {
+	var config *Config
+	if err := config.validateAndSetDefaults(); err != ErrNoConfiguration {
+		t.Errorf("expected error %v, got %v", ErrNoConfiguration, err)
+	}
+}
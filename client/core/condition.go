@@ -1,12 +1,12 @@
 package core
 
 import (
-	"errors"
 	"fmt"
 	"github.com/Masterminds/semver/v3"
+	"regexp"
 	"strconv"
 	"strings"
-	"time"
+	"unicode"
 
 	"github.com/serverless-aliyun/func-status/client/jsonpath"
 	"github.com/serverless-aliyun/func-status/client/pattern"
@@ -29,6 +29,27 @@ const (
 	// Values that could replace the placeholder: NOERROR, FORMERR, SERVFAIL, NXDOMAIN, NOTIMP, REFUSED
 	DNSRCodePlaceholder = "[DNS_RCODE]"
 
+	// DNSTTLPlaceholder is a placeholder for the lowest TTL of the records in the answer of a DNS query, in seconds.
+	//
+	// Values that could replace the placeholder: 0, 300, 3600, ...
+	DNSTTLPlaceholder = "[DNS_TTL]"
+
+	// DNSAuthoritativePlaceholder is a placeholder for whether the answer of a DNS query is authoritative.
+	//
+	// Values that could replace the placeholder: true, false
+	DNSAuthoritativePlaceholder = "[DNS_AUTHORITATIVE]"
+
+	// DNSSECPlaceholder is a placeholder for the DNSSEC status of the answer of a DNS query.
+	//
+	// Values that could replace the placeholder: secure, insecure, bogus
+	DNSSECPlaceholder = "[DNSSEC]"
+
+	// DNSSECExpirationPlaceholder is a placeholder for the duration before the first DNSSEC signature of the answer
+	// expires, in milliseconds.
+	//
+	// Values that could replace the placeholder: 4461677039 (~52 days)
+	DNSSECExpirationPlaceholder = "[DNSSEC_EXPIRATION]"
+
 	// ResponseTimePlaceholder is a placeholder for the request response time, in milliseconds.
 	//
 	// Values that could replace the placeholder: 1, 500, 1000, ...
@@ -49,6 +70,26 @@ const (
 	// Values that could replace the placeholder: 4461677039 (~52 days)
 	CertificateExpirationPlaceholder = "[CERTIFICATE_EXPIRATION]"
 
+	// PacketLossPlaceholder is a placeholder for the percentage of unanswered ICMP echo requests.
+	//
+	// Values that could replace the placeholder: 0, 33.333333333333336, 100, ...
+	PacketLossPlaceholder = "[PACKET_LOSS]"
+
+	// JitterPlaceholder is a placeholder for the jitter of ICMP echo requests, in milliseconds.
+	//
+	// Values that could replace the placeholder: 0, 5, 20, ...
+	JitterPlaceholder = "[JITTER]"
+
+	// GRPCStatusPlaceholder is a placeholder for the serving status reported by a gRPC health check.
+	//
+	// Values that could replace the placeholder: UNKNOWN, SERVING, NOT_SERVING, SERVICE_UNKNOWN
+	GRPCStatusPlaceholder = "[GRPC_STATUS]"
+
+	// BannerPlaceholder is a placeholder for the identification string sent by an SSH server.
+	//
+	// Values that could replace the placeholder: SSH-2.0-OpenSSH_9.6, ...
+	BannerPlaceholder = "[BANNER]"
+
 	// VersionPlaceholder is a placeholder for version check.
 	//
 	// Values that could replace the placeholder: 1.2.0, 1.2.3, ...
@@ -59,9 +100,24 @@ const (
 const (
 	// LengthFunctionPrefix is the prefix for the length function
 	//
-	// Usage: len([BODY].articles) == 10, len([BODY].name) > 5
+	// Usage: len([BODY].articles) == 10, len([BODY].name) > 5, len([BANNER]) < 64
 	LengthFunctionPrefix = "len("
 
+	// LowerFunctionPrefix is the prefix for the function converting a value to lowercase
+	//
+	// Usage: lower([BODY].status) == ok
+	LowerFunctionPrefix = "lower("
+
+	// UpperFunctionPrefix is the prefix for the function converting a value to uppercase
+	//
+	// Usage: upper([DNS_RCODE]) == NOERROR
+	UpperFunctionPrefix = "upper("
+
+	// TrimFunctionPrefix is the prefix for the function removing the leading and trailing white space of a value
+	//
+	// Usage: trim([BODY]) == pong
+	TrimFunctionPrefix = "trim("
+
 	// HasFunctionPrefix is the prefix for the has function
 	//
 	// Usage: has([BODY].errors) == true
@@ -77,6 +133,26 @@ const (
 	// Usage: [IP] == any(1.1.1.1, 1.0.0.1)
 	AnyFunctionPrefix = "any("
 
+	// RegexFunctionPrefix is the prefix for the regular expression function
+	//
+	// Usage: [BANNER] == re(^SSH-2\.0-OpenSSH_(?P<version>[\d.]+)), [BODY] != re((?i)error|exception)
+	RegexFunctionPrefix = "re("
+
+	// ContainsFunctionPrefix is the prefix for the function checking whether a value contains a substring
+	//
+	// Usage: [BODY] == contains(<h1>Welcome</h1>), [BODY] != contains(maintenance)
+	ContainsFunctionPrefix = "contains("
+
+	// StartsWithFunctionPrefix is the prefix for the function checking whether a value starts with a prefix
+	//
+	// Usage: [BANNER] == startsWith(SSH-2.0-)
+	StartsWithFunctionPrefix = "startsWith("
+
+	// EndsWithFunctionPrefix is the prefix for the function checking whether a value ends with a suffix
+	//
+	// Usage: [BODY].email == endsWith(@example.org)
+	EndsWithFunctionPrefix = "endsWith("
+
 	// FunctionSuffix is the suffix for all functions
 	FunctionSuffix = ")"
 )
@@ -99,78 +175,44 @@ type Condition string
 
 // Validate checks if the Condition is valid
 func (c Condition) Validate() error {
-	r := &Result{}
-	c.evaluate(r, false)
-	if len(r.Errors) != 0 {
-		return errors.New(r.Errors[0])
+	_, err := c.compileAndValidate()
+	return err
+}
+
+// compileAndValidate compiles the Condition, and rejects the comparisons that can be compiled but will never work,
+// such as comparing a value with a constant that isn't a number using <, <=, > or >=
+func (c Condition) compileAndValidate() (conditionExpression, error) {
+	expression, err := c.compile()
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	if err := validateConditionExpression(expression); err != nil {
+		return nil, err
+	}
+	return expression, nil
+}
+
+// compile returns the evaluator of the Condition.
+//
+// Compiling a Condition parses it, detects the comparators, placeholders and functions of its comparisons and converts
+// its constants, so that it can be evaluated against as many results as needed.
+func (c Condition) compile() (conditionExpression, error) {
+	return parseCondition(string(c))
 }
 
 // evaluate the Condition with the Result of the health check
 func (c Condition) evaluate(result *Result, dontResolveFailedConditions bool) bool {
-	condition := string(c)
-	success := false
-	conditionToDisplay := condition
-	if strings.Contains(condition, VersionPlaceholder) {
-		constraints := strings.ReplaceAll(condition, VersionPlaceholder, "")
-		parameters, resolvedParameters := sanitizeAndResolve([]string{VersionPlaceholder, constraints}, result)
-		nowVersion, err := semver.NewVersion(resolvedParameters[0])
-		if err != nil {
-			success = false
-		} else {
-			checkVersion, _ := semver.NewConstraint(resolvedParameters[1])
-			success = checkVersion.Check(nowVersion)
-		}
-		if !success && !dontResolveFailedConditions {
-			conditionToDisplay = prettify(parameters, resolvedParameters, "!=")
-		}
-		result.ConditionResults = append(result.ConditionResults, &ConditionResult{Condition: conditionToDisplay, Success: success})
-		return success
-	}
-	if strings.Contains(condition, " == ") {
-		parameters, resolvedParameters := sanitizeAndResolve(strings.Split(condition, " == "), result)
-		success = isEqual(resolvedParameters[0], resolvedParameters[1])
-		if !success && !dontResolveFailedConditions {
-			conditionToDisplay = prettify(parameters, resolvedParameters, "==")
-		}
-	} else if strings.Contains(condition, " != ") {
-		parameters, resolvedParameters := sanitizeAndResolve(strings.Split(condition, " != "), result)
-		success = !isEqual(resolvedParameters[0], resolvedParameters[1])
-		if !success && !dontResolveFailedConditions {
-			conditionToDisplay = prettify(parameters, resolvedParameters, "!=")
-		}
-	} else if strings.Contains(condition, " <= ") {
-		parameters, resolvedParameters := sanitizeAndResolveNumerical(strings.Split(condition, " <= "), result)
-		success = resolvedParameters[0] <= resolvedParameters[1]
-		if !success && !dontResolveFailedConditions {
-			conditionToDisplay = prettifyNumericalParameters(parameters, resolvedParameters, "<=")
-		}
-	} else if strings.Contains(condition, " >= ") {
-		parameters, resolvedParameters := sanitizeAndResolveNumerical(strings.Split(condition, " >= "), result)
-		success = resolvedParameters[0] >= resolvedParameters[1]
-		if !success && !dontResolveFailedConditions {
-			conditionToDisplay = prettifyNumericalParameters(parameters, resolvedParameters, ">=")
-		}
-	} else if strings.Contains(condition, " > ") {
-		parameters, resolvedParameters := sanitizeAndResolveNumerical(strings.Split(condition, " > "), result)
-		success = resolvedParameters[0] > resolvedParameters[1]
-		if !success && !dontResolveFailedConditions {
-			conditionToDisplay = prettifyNumericalParameters(parameters, resolvedParameters, ">")
-		}
-	} else if strings.Contains(condition, " < ") {
-		parameters, resolvedParameters := sanitizeAndResolveNumerical(strings.Split(condition, " < "), result)
-		success = resolvedParameters[0] < resolvedParameters[1]
-		if !success && !dontResolveFailedConditions {
-			conditionToDisplay = prettifyNumericalParameters(parameters, resolvedParameters, "<")
-		}
-	} else {
-		result.AddError(fmt.Sprintf("invalid condition: %s", condition))
+	expression, err := c.compile()
+	if err != nil {
+		result.AddError(err.Error())
 		return false
 	}
-	if !success {
-		//log.Printf("[Condition][evaluate] Condition '%s' did not succeed because '%s' is false", condition, condition)
-	}
+	return evaluateConditionExpression(expression, result, dontResolveFailedConditions)
+}
+
+// evaluateConditionExpression evaluates a compiled Condition and adds its ConditionResult to the Result
+func evaluateConditionExpression(expression conditionExpression, result *Result, dontResolveFailedConditions bool) bool {
+	success, conditionToDisplay := expression.evaluate(result, dontResolveFailedConditions)
 	result.ConditionResults = append(result.ConditionResults, &ConditionResult{Condition: conditionToDisplay, Success: success})
 	return success
 }
@@ -220,131 +262,467 @@ func isEqual(first, second string) bool {
 			second = strings.TrimSuffix(strings.TrimPrefix(second, AnyFunctionPrefix), FunctionSuffix)
 		}
 		if isFirstAny && !isSecondAny {
-			options := strings.Split(first, ",")
-			for _, option := range options {
-				if strings.TrimSpace(option) == second {
-					return true
-				}
-			}
-			return false
+			return isAnyOf(second, first)
 		} else if !isFirstAny && isSecondAny {
-			options := strings.Split(second, ",")
-			for _, option := range options {
-				if strings.TrimSpace(option) == first {
-					return true
-				}
-			}
-			return false
+			return isAnyOf(first, second)
 		}
 	}
 
 	// test if inputs are integers
-	firstInt, err1 := strconv.ParseInt(first, 0, 64)
-	secondInt, err2 := strconv.ParseInt(second, 0, 64)
-	if err1 == nil && err2 == nil {
-		return firstInt == secondInt
+	if startsLikeNumber(first) && startsLikeNumber(second) {
+		firstInt, err1 := strconv.ParseInt(first, 0, 64)
+		secondInt, err2 := strconv.ParseInt(second, 0, 64)
+		if err1 == nil && err2 == nil {
+			return firstInt == secondInt
+		}
 	}
 
 	return first == second
 }
 
-// sanitizeAndResolve sanitizes and resolves a list of elements and returns the list of parameters as well as a list
-// of resolved parameters
-func sanitizeAndResolve(elements []string, result *Result) ([]string, []string) {
-	parameters := make([]string, len(elements))
-	resolvedParameters := make([]string, len(elements))
-	body := strings.TrimSpace(string(result.Body))
-	for i, element := range elements {
-		element = strings.TrimSpace(element)
-		parameters[i] = element
-		switch strings.ToUpper(element) {
-		case StatusPlaceholder:
-			element = strconv.Itoa(result.HTTPStatus)
-		case IPPlaceholder:
-			element = result.IP
-		case ResponseTimePlaceholder:
-			element = strconv.Itoa(int(result.Duration.Milliseconds()))
-		case BodyPlaceholder:
-			element = body
-		case DNSRCodePlaceholder:
-			element = result.DNSRCode
-		case ConnectedPlaceholder:
-			element = strconv.FormatBool(result.Connected)
-		case CertificateExpirationPlaceholder:
-			element = strconv.FormatInt(result.CertificateExpiration.Milliseconds(), 10)
-		case VersionPlaceholder:
-			resolvedElement, _, _ := jsonpath.Eval("data", result.Body)
-			element = resolvedElement
-		default:
-			// if contains the BodyPlaceholder, then evaluate json path
-			if strings.Contains(element, BodyPlaceholder) {
-				checkingForLength := false
-				checkingForExistence := false
-				if strings.HasPrefix(element, LengthFunctionPrefix) && strings.HasSuffix(element, FunctionSuffix) {
-					checkingForLength = true
-					element = strings.TrimSuffix(strings.TrimPrefix(element, LengthFunctionPrefix), FunctionSuffix)
-				}
-				if strings.HasPrefix(element, HasFunctionPrefix) && strings.HasSuffix(element, FunctionSuffix) {
-					checkingForExistence = true
-					element = strings.TrimSuffix(strings.TrimPrefix(element, HasFunctionPrefix), FunctionSuffix)
-				}
-				resolvedElement, resolvedElementLength, err := jsonpath.Eval(strings.TrimPrefix(strings.TrimPrefix(element, BodyPlaceholder), "."), result.Body)
-				if checkingForExistence {
-					if err != nil {
-						element = "false"
-					} else {
-						element = "true"
-					}
-				} else {
-					if err != nil {
-						if err.Error() != "unexpected end of JSON input" {
-							result.AddError(err.Error())
-						}
-						if checkingForLength {
-							element = LengthFunctionPrefix + element + FunctionSuffix + " " + InvalidConditionElementSuffix
-						} else {
-							element = element + " " + InvalidConditionElementSuffix
-						}
-					} else {
-						if checkingForLength {
-							element = strconv.Itoa(resolvedElementLength)
-						} else {
-							element = resolvedElement
-						}
-					}
-				}
-			}
+// isAnyOf checks whether the value is one of the comma-separated options
+func isAnyOf(value, options string) bool {
+	for {
+		option, rest, found := strings.Cut(options, ",")
+		if strings.TrimSpace(option) == value {
+			return true
+		}
+		if !found {
+			return false
 		}
-		resolvedParameters[i] = element
-	}
-	return parameters, resolvedParameters
-}
-
-func sanitizeAndResolveNumerical(list []string, result *Result) (parameters []string, resolvedNumericalParameters []int64) {
-	parameters, resolvedParameters := sanitizeAndResolve(list, result)
-	for _, element := range resolvedParameters {
-		if duration, err := time.ParseDuration(element); duration != 0 && err == nil {
-			// If the string is a duration, convert it to milliseconds
-			resolvedNumericalParameters = append(resolvedNumericalParameters, duration.Milliseconds())
-		} else if number, err := strconv.ParseInt(element, 0, 64); err != nil {
-			// It's not an int, so we'll check if it's a float
-			if f, err := strconv.ParseFloat(element, 64); err == nil {
-				// It's a float, but we'll convert it to an int. We're losing precision here, but it's better than
-				// just returning 0.
-				resolvedNumericalParameters = append(resolvedNumericalParameters, int64(f))
-			} else {
-				// Default to 0 if the string couldn't be converted to an integer or a float
-				resolvedNumericalParameters = append(resolvedNumericalParameters, 0)
+		options = rest
+	}
+}
+
+// startsLikeNumber checks whether the value starts like a number, which avoids parsing values that are obviously not
+// numbers
+func startsLikeNumber(value string) bool {
+	return len(value) > 0 && (value[0] == '-' || value[0] == '+' || (value[0] >= '0' && value[0] <= '9'))
+}
+
+// comparators are the comparators supported by comparisons, in the order in which they are looked for
+var comparators = []string{"==", "!=", "<=", ">=", ">", "<"}
+
+// placeholderResolvers are the functions resolving each placeholder, except for the BodyPlaceholder with a JSONPath
+var placeholderResolvers = map[string]func(result *Result) string{
+	StatusPlaceholder:                func(result *Result) string { return strconv.Itoa(result.HTTPStatus) },
+	IPPlaceholder:                    func(result *Result) string { return result.IP },
+	ResponseTimePlaceholder:          func(result *Result) string { return strconv.Itoa(int(result.Duration.Milliseconds())) },
+	BodyPlaceholder:                  func(result *Result) string { return strings.TrimSpace(string(result.Body)) },
+	DNSRCodePlaceholder:              func(result *Result) string { return result.DNSRCode },
+	DNSTTLPlaceholder:                func(result *Result) string { return strconv.FormatUint(uint64(result.DNSTTL), 10) },
+	DNSAuthoritativePlaceholder:      func(result *Result) string { return strconv.FormatBool(result.DNSAuthoritative) },
+	DNSSECPlaceholder:                func(result *Result) string { return result.DNSSEC },
+	DNSSECExpirationPlaceholder:      func(result *Result) string { return strconv.FormatInt(result.DNSSECExpiration.Milliseconds(), 10) },
+	ConnectedPlaceholder:             func(result *Result) string { return strconv.FormatBool(result.Connected) },
+	CertificateExpirationPlaceholder: func(result *Result) string { return strconv.FormatInt(result.CertificateExpiration.Milliseconds(), 10) },
+	PacketLossPlaceholder:            func(result *Result) string { return strconv.FormatFloat(result.PacketLoss, 'f', -1, 64) },
+	JitterPlaceholder:                func(result *Result) string { return strconv.FormatInt(result.Jitter.Milliseconds(), 10) },
+	GRPCStatusPlaceholder:            func(result *Result) string { return result.GRPCStatus },
+	BannerPlaceholder:                func(result *Result) string { return result.Banner },
+	VersionPlaceholder: func(result *Result) string {
+		resolvedElement, _, _ := jsonpath.Eval("data", result.Body)
+		return resolvedElement
+	},
+}
+
+// comparisonExpression is a compiled comparison between two operands, e.g. [STATUS] == 200
+type comparisonExpression struct {
+	comparison string
+	comparator string
+	operands   [2]*conditionOperand
+
+	// versionConstraints are the constraints the VersionPlaceholder is checked against. If set, the comparison has no
+	// comparator, e.g. [VERSION] ~1.2.3
+	versionConstraints *semver.Constraints
+}
+
+// compileComparison compiles a comparison of a Condition
+func compileComparison(comparison string) (*comparisonExpression, error) {
+	if strings.Contains(comparison, VersionPlaceholder) {
+		constraints := strings.TrimSpace(strings.ReplaceAll(comparison, VersionPlaceholder, ""))
+		versionConstraints, err := semver.NewConstraint(constraints)
+		if err != nil {
+			return nil, fmt.Errorf("invalid condition: %s: %w", comparison, err)
+		}
+		version, _ := compileOperand(VersionPlaceholder)
+		return &comparisonExpression{
+			comparison:         comparison,
+			operands:           [2]*conditionOperand{version, {parameter: constraints}},
+			versionConstraints: versionConstraints,
+		}, nil
+	}
+	for _, comparator := range comparators {
+		parameters := strings.Split(comparison, " "+comparator+" ")
+		if len(parameters) < 2 {
+			continue
+		}
+		e := &comparisonExpression{comparison: comparison, comparator: comparator}
+		for i := range e.operands {
+			operand, err := compileOperand(parameters[i])
+			if err != nil {
+				return nil, fmt.Errorf("invalid condition: %s: %w", comparison, err)
+			}
+			if operand.isMatcher() && comparator != "==" && comparator != "!=" {
+				function, _, _ := splitFunction(operand.parameter)
+				return nil, fmt.Errorf("invalid condition: %s: %s can only be used with == and !=", comparison, function+FunctionSuffix)
 			}
+			e.operands[i] = operand
+		}
+		if e.operands[0].isMatcher() && e.operands[1].isMatcher() {
+			return nil, fmt.Errorf("invalid condition: %s: only one of the values can be a function matching the other", comparison)
+		}
+		return e, nil
+	}
+	return nil, fmt.Errorf("invalid condition: %s", comparison)
+}
+
+func (e *comparisonExpression) evaluate(result *Result, dontResolveFailedConditions bool) (bool, string) {
+	first, second := e.operands[0].resolve(result), e.operands[1].resolve(result)
+	var success bool
+	var numericalParameters []conditionNumber
+	switch e.comparator {
+	case "":
+		version, err := semver.NewVersion(first)
+		success = err == nil && e.versionConstraints.Check(version)
+		// A version that doesn't match its constraints is displayed as [VERSION] (1.3.5) != ~1.2.3
+		if !success && !dontResolveFailedConditions {
+			return false, prettify(e.parameters(), []string{first, second}, "!=")
+		}
+		return success, e.comparison
+	case "==", "!=":
+		if re, value := e.regexpAndValue(first, second); re != nil {
+			return e.evaluateRegexp(re, first, second, value, dontResolveFailedConditions)
+		}
+		if e.operands[0].matcher != nil {
+			success = e.operands[0].matcher(second) == (e.comparator == "==")
+		} else if e.operands[1].matcher != nil {
+			success = e.operands[1].matcher(first) == (e.comparator == "==")
 		} else {
-			resolvedNumericalParameters = append(resolvedNumericalParameters, number)
+			success = isEqual(first, second) == (e.comparator == "==")
+		}
+	default:
+		firstNumber, firstIsNumber := e.operands[0].toNumber(first)
+		secondNumber, secondIsNumber := e.operands[1].toNumber(second)
+		if !firstIsNumber || !secondIsNumber {
+			// Values that aren't numbers, such as the value of a missing JSON path, can't be compared
+			if !firstIsNumber {
+				result.AddError(fmt.Sprintf("cannot evaluate %s: %q is not a number", e.comparison, first))
+			}
+			if !secondIsNumber {
+				result.AddError(fmt.Sprintf("cannot evaluate %s: %q is not a number", e.comparison, second))
+			}
+			if dontResolveFailedConditions {
+				return false, e.comparison
+			}
+			return false, prettify(e.parameters(), []string{first, second}, e.comparator)
+		}
+		numericalParameters = []conditionNumber{firstNumber, secondNumber}
+		comparison := numericalParameters[0].compare(numericalParameters[1])
+		switch e.comparator {
+		case "<=":
+			success = comparison <= 0
+		case ">=":
+			success = comparison >= 0
+		case ">":
+			success = comparison > 0
+		case "<":
+			success = comparison < 0
 		}
 	}
-	return parameters, resolvedNumericalParameters
+	if success || dontResolveFailedConditions {
+		return success, e.comparison
+	}
+	if numericalParameters != nil {
+		return false, prettifyNumericalParameters(e.parameters(), numericalParameters, e.comparator)
+	}
+	return false, prettify(e.parameters(), []string{first, second}, e.comparator)
+}
+
+func (e *comparisonExpression) String() string {
+	return e.comparison
+}
+
+// validate checks that the constants compared with <, <=, > or >= are numbers
+func (e *comparisonExpression) validate() error {
+	switch e.comparator {
+	case "", "==", "!=":
+		return nil
+	}
+	for _, operand := range e.operands {
+		if operand.resolver == nil && !operand.isNumber {
+			return fmt.Errorf("invalid condition: %s: %s is not a number", e.comparison, operand.parameter)
+		}
+	}
+	return nil
+}
+
+// regexpAndValue returns the regular expression of the comparison if one of the operands is a re function, as well
+// as the resolved value of the other operand
+func (e *comparisonExpression) regexpAndValue(first, second string) (*regexp.Regexp, string) {
+	if e.operands[0].regexp != nil {
+		return e.operands[0].regexp, second
+	} else if e.operands[1].regexp != nil {
+		return e.operands[1].regexp, first
+	}
+	return nil, ""
+}
+
+// evaluateRegexp evaluates a comparison with the re function. If the value matches the regular expression, the values
+// of its named capture groups are displayed after the comparison, e.g. [BANNER] == re(OpenSSH_(?P<version>\S+)) (version=9.6)
+func (e *comparisonExpression) evaluateRegexp(re *regexp.Regexp, first, second, value string, dontResolveFailedConditions bool) (bool, string) {
+	matches := re.FindStringSubmatch(value)
+	success := (matches != nil) == (e.comparator == "==")
+	if !success && dontResolveFailedConditions {
+		return false, e.comparison
+	}
+	conditionToDisplay := e.comparison
+	if !success {
+		conditionToDisplay = prettify(e.parameters(), []string{first, second}, e.comparator)
+	}
+	var captures []string
+	for i, name := range re.SubexpNames() {
+		if len(name) > 0 && matches != nil {
+			captures = append(captures, name+"="+matches[i])
+		}
+	}
+	if len(captures) > 0 {
+		conditionToDisplay += " (" + strings.Join(captures, ", ") + ")"
+	}
+	return success, conditionToDisplay
+}
+
+// parameters returns the operands as written in the comparison
+func (e *comparisonExpression) parameters() []string {
+	return []string{e.operands[0].parameter, e.operands[1].parameter}
+}
+
+// conditionOperand is a compiled operand of a comparison
+type conditionOperand struct {
+	// parameter is the operand as written in the comparison
+	parameter string
+
+	// resolver returns the value of the operand for a Result. It is nil for constants.
+	resolver func(result *Result) string
+
+	// number is the numerical value of a constant
+	number conditionNumber
+
+	// isNumber is whether the constant is a number. Constants that aren't numbers are rejected by Condition.Validate,
+	// and compared as 0 otherwise.
+	isNumber bool
+
+	// regexp is the compiled regular expression of a constant using the re function
+	regexp *regexp.Regexp
+
+	// matcher checks whether the other operand matches a constant using the contains, startsWith or endsWith function
+	matcher func(value string) bool
+}
+
+// stringTransformations are the functions that can be applied to the value of any placeholder
+var stringTransformations = map[string]func(value string) string{
+	LowerFunctionPrefix:  strings.ToLower,
+	UpperFunctionPrefix:  strings.ToUpper,
+	TrimFunctionPrefix:   strings.TrimSpace,
+	LengthFunctionPrefix: func(value string) string { return strconv.Itoa(len(value)) },
+}
+
+// stringMatchers are the functions matching the other operand of a comparison against their argument
+var stringMatchers = map[string]func(value, argument string) bool{
+	ContainsFunctionPrefix:   strings.Contains,
+	StartsWithFunctionPrefix: strings.HasPrefix,
+	EndsWithFunctionPrefix:   strings.HasSuffix,
+}
+
+// compileOperand compiles an operand of a comparison, which is either a placeholder, a function of a placeholder, a
+// function matching the other operand or a constant
+func compileOperand(parameter string) (*conditionOperand, error) {
+	operand := &conditionOperand{parameter: strings.TrimSpace(parameter)}
+	if resolver, ok := placeholderResolvers[strings.ToUpper(operand.parameter)]; ok {
+		operand.resolver = resolver
+		return operand, nil
+	}
+	// The length of a JSONPath is the number of elements of an array, so it is resolved by the body operand
+	if function, argument, ok := splitFunction(operand.parameter); ok && stringTransformations[function] != nil && !(function == LengthFunctionPrefix && strings.HasPrefix(argument, BodyPlaceholder)) {
+		inner, err := compileOperand(argument)
+		if err != nil {
+			return nil, err
+		}
+		if inner.resolver == nil {
+			return nil, fmt.Errorf("%s must be used with a placeholder", function+FunctionSuffix)
+		}
+		transformation := stringTransformations[function]
+		operand.resolver = func(result *Result) string {
+			value := inner.resolver(result)
+			if strings.HasSuffix(value, InvalidConditionElementSuffix) {
+				return value
+			}
+			return transformation(value)
+		}
+		return operand, nil
+	}
+	if function, argument, ok := splitFunction(operand.parameter); ok {
+		if err := validateFunctionOfPlaceholder(function, argument); err != nil {
+			return nil, err
+		}
+	}
+	if strings.Contains(operand.parameter, BodyPlaceholder) {
+		operand.resolver = compileBodyOperand(operand.parameter)
+		return operand, nil
+	}
+	if function, argument, ok := splitFunction(operand.parameter); ok && function == RegexFunctionPrefix {
+		re, err := regexp.Compile(argument)
+		if err != nil {
+			return nil, err
+		}
+		operand.regexp = re
+		return operand, nil
+	}
+	if function, argument, ok := splitFunction(operand.parameter); ok && stringMatchers[function] != nil {
+		if len(argument) == 0 {
+			return nil, fmt.Errorf("%s requires a value", function+FunctionSuffix)
+		}
+		match := stringMatchers[function]
+		operand.matcher = func(value string) bool {
+			return match(value, argument)
+		}
+		return operand, nil
+	}
+	operand.number, operand.isNumber = toNumber(operand.parameter)
+	return operand, nil
 }
 
-func prettifyNumericalParameters(parameters []string, resolvedParameters []int64, operator string) string {
-	return prettify(parameters, []string{strconv.Itoa(int(resolvedParameters[0])), strconv.Itoa(int(resolvedParameters[1]))}, operator)
+// splitFunction returns the prefix of the function used by a parameter, e.g. lower(, as well as its argument
+func splitFunction(parameter string) (string, string, bool) {
+	openingParenthesis := strings.Index(parameter, "(")
+	if openingParenthesis <= 0 || !strings.HasSuffix(parameter, FunctionSuffix) {
+		return "", "", false
+	}
+	return parameter[:openingParenthesis+1], parameter[openingParenthesis+1 : len(parameter)-len(FunctionSuffix)], true
+}
+
+// validateFunctionOfPlaceholder checks that a function wrapping a placeholder, other than the lower, upper, trim and len
+// functions which are compiled beforehand, can be applied to it. Otherwise, the operand would be compared as a constant.
+func validateFunctionOfPlaceholder(function, argument string) error {
+	switch {
+	case !isKnownFunction(function):
+		if isFunctionName(function) && containsPlaceholder(argument) {
+			return fmt.Errorf("unknown function %s", function+FunctionSuffix)
+		}
+	case function == LengthFunctionPrefix:
+		// The length of a JSONPath is resolved by the body operand, and the length of anything else is compiled beforehand
+	case function == HasFunctionPrefix:
+		if !strings.HasPrefix(argument, BodyPlaceholder) {
+			return fmt.Errorf("%s only works with the %s placeholder", function+FunctionSuffix, BodyPlaceholder)
+		}
+	case startsWithPlaceholder(argument):
+		return fmt.Errorf("%s doesn't work with placeholders", function+FunctionSuffix)
+	}
+	return nil
+}
+
+// isKnownFunction checks whether the prefix of a function, e.g. lower(, is one of the supported functions
+func isKnownFunction(function string) bool {
+	switch function {
+	case HasFunctionPrefix, PatternFunctionPrefix, AnyFunctionPrefix, RegexFunctionPrefix:
+		return true
+	}
+	return stringTransformations[function] != nil || stringMatchers[function] != nil
+}
+
+// isFunctionName checks whether the prefix of a function, e.g. lower(, is made of a name followed by a parenthesis,
+// which tells a function apart from a value that happens to contain parentheses
+func isFunctionName(function string) bool {
+	name := strings.TrimSuffix(function, "(")
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return false
+		}
+	}
+	return len(name) > 0
+}
+
+// containsPlaceholder checks whether a value contains one of the placeholders
+func containsPlaceholder(value string) bool {
+	value = strings.ToUpper(value)
+	for placeholder := range placeholderResolvers {
+		if strings.Contains(value, placeholder) {
+			return true
+		}
+	}
+	return false
+}
+
+// startsWithPlaceholder checks whether a value starts with one of the placeholders
+func startsWithPlaceholder(value string) bool {
+	value = strings.ToUpper(value)
+	for placeholder := range placeholderResolvers {
+		if strings.HasPrefix(value, placeholder) {
+			return true
+		}
+	}
+	return false
+}
+
+// isMatcher checks whether the operand is a function matching the other operand of the comparison
+func (o *conditionOperand) isMatcher() bool {
+	return o.regexp != nil || o.matcher != nil
+}
+
+// compileBodyOperand returns the resolver of an operand evaluating a JSONPath against the body, such as [BODY].name,
+// len([BODY].data) or has([BODY].errors)
+func compileBodyOperand(element string) func(result *Result) string {
+	checkingForLength := false
+	checkingForExistence := false
+	if strings.HasPrefix(element, LengthFunctionPrefix) && strings.HasSuffix(element, FunctionSuffix) {
+		checkingForLength = true
+		element = strings.TrimSuffix(strings.TrimPrefix(element, LengthFunctionPrefix), FunctionSuffix)
+	}
+	if strings.HasPrefix(element, HasFunctionPrefix) && strings.HasSuffix(element, FunctionSuffix) {
+		checkingForExistence = true
+		element = strings.TrimSuffix(strings.TrimPrefix(element, HasFunctionPrefix), FunctionSuffix)
+	}
+	path := strings.TrimPrefix(strings.TrimPrefix(element, BodyPlaceholder), ".")
+	invalidElement := element + " " + InvalidConditionElementSuffix
+	if checkingForLength {
+		invalidElement = LengthFunctionPrefix + element + FunctionSuffix + " " + InvalidConditionElementSuffix
+	}
+	return func(result *Result) string {
+		resolvedElement, resolvedElementLength, err := jsonpath.Eval(path, result.Body)
+		if checkingForExistence {
+			return strconv.FormatBool(err == nil)
+		}
+		if err != nil {
+			if err.Error() != "unexpected end of JSON input" {
+				result.AddError(err.Error())
+			}
+			return invalidElement
+		}
+		if checkingForLength {
+			return strconv.Itoa(resolvedElementLength)
+		}
+		return resolvedElement
+	}
+}
+
+// resolve returns the value of the operand for a Result
+func (o *conditionOperand) resolve(result *Result) string {
+	if o.resolver == nil {
+		return o.parameter
+	}
+	return o.resolver(result)
+}
+
+// toNumber returns the numerical value of the resolved operand, and whether it is a number
+func (o *conditionOperand) toNumber(resolved string) (conditionNumber, bool) {
+	if o.resolver == nil {
+		return o.number, true
+	}
+	return toNumber(resolved)
+}
+
+func prettifyNumericalParameters(parameters []string, resolvedParameters []conditionNumber, operator string) string {
+	return prettify(parameters, []string{resolvedParameters[0].String(), resolvedParameters[1].String()}, operator)
 }
 
 // prettify returns a string representation of a condition with its parameters resolved between parentheses
@@ -354,11 +732,12 @@ func prettify(parameters []string, resolvedParameters []string, operator string)
 	if strings.HasSuffix(resolvedParameters[0], InvalidConditionElementSuffix) || strings.HasSuffix(resolvedParameters[1], InvalidConditionElementSuffix) {
 		return resolvedParameters[0] + " " + operator + " " + resolvedParameters[1]
 	}
-	// If using the pattern function, truncate the parameter it's being compared to if said parameter is long enough
-	if strings.HasPrefix(parameters[0], PatternFunctionPrefix) && strings.HasSuffix(parameters[0], FunctionSuffix) && len(resolvedParameters[1]) > maximumLengthBeforeTruncatingWhenComparedWithPattern {
+	// If using a function matching the other parameter, such as pat, truncate the parameter it's being compared to if
+	// said parameter is long enough
+	if isMatchingFunction(parameters[0]) && len(resolvedParameters[1]) > maximumLengthBeforeTruncatingWhenComparedWithPattern {
 		resolvedParameters[1] = fmt.Sprintf("%.25s...(truncated)", resolvedParameters[1])
 	}
-	if strings.HasPrefix(parameters[1], PatternFunctionPrefix) && strings.HasSuffix(parameters[1], FunctionSuffix) && len(resolvedParameters[0]) > maximumLengthBeforeTruncatingWhenComparedWithPattern {
+	if isMatchingFunction(parameters[1]) && len(resolvedParameters[0]) > maximumLengthBeforeTruncatingWhenComparedWithPattern {
 		resolvedParameters[0] = fmt.Sprintf("%.25s...(truncated)", resolvedParameters[0])
 	}
 	// First element is a placeholder
@@ -376,3 +755,9 @@ func prettify(parameters []string, resolvedParameters []string, operator string)
 	// Neither elements are placeholders
 	return parameters[0] + " " + operator + " " + parameters[1]
 }
+
+// isMatchingFunction checks whether the parameter uses one of the functions matching the other parameter, except for any
+func isMatchingFunction(parameter string) bool {
+	function, _, ok := splitFunction(parameter)
+	return ok && (function == PatternFunctionPrefix || function == RegexFunctionPrefix || stringMatchers[function] != nil)
+}
@@ -82,3 +82,34 @@ func BenchmarkCondition_evaluateWithStatusFailure(b *testing.B) {
 	}
 	b.ReportAllocs()
 }
+
+func BenchmarkCondition_evaluateCompiled(b *testing.B) {
+	scenarios := []struct {
+		name      string
+		condition Condition
+		result    Result
+	}{
+		{name: "body-string-any", condition: "[BODY].name == any(john.doe, jane.doe)", result: Result{Body: []byte("{\"name\": \"john.doe\"}")}},
+		{name: "body-string-any-failure", condition: "[BODY].name == any(john.doe, jane.doe)", result: Result{Body: []byte("{\"name\": \"bob.doe\"}")}},
+		{name: "body-string", condition: "[BODY].name == john.doe", result: Result{Body: []byte("{\"name\": \"john.doe\"}")}},
+		{name: "body-string-failure", condition: "[BODY].name == john.doe", result: Result{Body: []byte("{\"name\": \"bob.doe\"}")}},
+		{name: "body-string-failure-invalid-path", condition: "[BODY].user.name == bob.doe", result: Result{Body: []byte("{\"name\": \"bob.doe\"}")}},
+		{name: "body-string-len", condition: "len([BODY].name) == 8", result: Result{Body: []byte("{\"name\": \"john.doe\"}")}},
+		{name: "body-string-len-failure", condition: "len([BODY].name) == 8", result: Result{Body: []byte("{\"name\": \"bob.doe\"}")}},
+		{name: "status", condition: "[STATUS] == 200", result: Result{HTTPStatus: 200}},
+		{name: "status-failure", condition: "[STATUS] == 200", result: Result{HTTPStatus: 400}},
+	}
+	for _, scenario := range scenarios {
+		b.Run(scenario.name, func(b *testing.B) {
+			expression, err := scenario.condition.compile()
+			if err != nil {
+				b.Fatal(err)
+			}
+			for n := 0; n < b.N; n++ {
+				result := &Result{Body: scenario.result.Body, HTTPStatus: scenario.result.HTTPStatus}
+				evaluateConditionExpression(expression, result, false)
+			}
+			b.ReportAllocs()
+		})
+	}
+}
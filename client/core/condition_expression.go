@@ -0,0 +1,343 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Logical operators
+const (
+	// AndOperator is the operator that requires both of the expressions surrounding it to be true
+	//
+	// Usage: [STATUS] == 503 && [BODY].status == maintenance
+	AndOperator = "&&"
+
+	// OrOperator is the operator that requires one of the expressions surrounding it to be true
+	//
+	// Usage: [STATUS] == 200 || [STATUS] == 503
+	OrOperator = "||"
+
+	// NotOperator is the operator that negates the expression following it
+	//
+	// Usage: !([STATUS] == 503 && [BODY].status == maintenance)
+	NotOperator = "!"
+)
+
+type conditionTokenType int
+
+const (
+	comparisonToken conditionTokenType = iota
+	andToken
+	orToken
+	notToken
+	openingParenthesisToken
+	closingParenthesisToken
+)
+
+// conditionToken is a token of a Condition, along with its position in the Condition
+type conditionToken struct {
+	tokenType conditionTokenType
+	value     string
+	position  int
+}
+
+// tokenizeCondition splits a condition into comparisons, logical operators and parentheses.
+//
+// A comparison spans until the next logical operator or closing parenthesis of an open group, which means that the
+// parentheses of functions such as len([BODY].data) or any(200, 201) are part of the comparison.
+func tokenizeCondition(condition string) ([]conditionToken, error) {
+	var tokens []conditionToken
+	expectingOperand := true
+	openGroups := 0
+	for i := 0; i < len(condition); {
+		if condition[i] == ' ' {
+			i++
+			continue
+		}
+		if expectingOperand {
+			if condition[i] == '(' {
+				tokens = append(tokens, conditionToken{tokenType: openingParenthesisToken, value: "(", position: i})
+				openGroups++
+				i++
+				continue
+			}
+			if strings.HasPrefix(condition[i:], NotOperator) && !strings.HasPrefix(condition[i:], "!=") {
+				tokens = append(tokens, conditionToken{tokenType: notToken, value: NotOperator, position: i})
+				i++
+				continue
+			}
+			end := endOfComparison(condition, i, openGroups)
+			if end == i {
+				return nil, fmt.Errorf("expected a comparison at position %d", i)
+			}
+			tokens = append(tokens, conditionToken{tokenType: comparisonToken, value: strings.TrimSpace(condition[i:end]), position: i})
+			i = end
+			expectingOperand = false
+			continue
+		}
+		switch {
+		case strings.HasPrefix(condition[i:], AndOperator):
+			tokens = append(tokens, conditionToken{tokenType: andToken, value: AndOperator, position: i})
+			i += len(AndOperator)
+			expectingOperand = true
+		case strings.HasPrefix(condition[i:], OrOperator):
+			tokens = append(tokens, conditionToken{tokenType: orToken, value: OrOperator, position: i})
+			i += len(OrOperator)
+			expectingOperand = true
+		case condition[i] == ')':
+			tokens = append(tokens, conditionToken{tokenType: closingParenthesisToken, value: ")", position: i})
+			openGroups--
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected '%c' at position %d", condition[i], i)
+		}
+	}
+	return tokens, nil
+}
+
+// endOfComparison returns the position at which the comparison starting at the given position ends.
+//
+// Values may contain parentheses and logical operators, e.g. [BODY] == :) or [BODY] == a|b||c, so a closing
+// parenthesis only ends the comparison if a group is open, and a logical operator only ends it if another comparison
+// follows it.
+func endOfComparison(condition string, start, openGroups int) int {
+	// The constraints of the version placeholder use || to separate ranges, e.g. [VERSION] ^1.2 || ^2.0
+	isVersionConstraint := strings.HasPrefix(condition[start:], VersionPlaceholder)
+	depth := 0
+	for i := start; i < len(condition); i++ {
+		switch {
+		case condition[i] == '\\':
+			// Escaped characters, such as the parentheses of a regular expression, are part of the comparison
+			i++
+		case condition[i] == '(':
+			depth++
+		case condition[i] == ')':
+			if depth > 0 {
+				depth--
+			} else if openGroups > 0 {
+				return i
+			}
+		case depth == 0 && strings.HasPrefix(condition[i:], AndOperator) && startsComparison(condition[i+len(AndOperator):]):
+			return i
+		case depth == 0 && strings.HasPrefix(condition[i:], OrOperator) && !isVersionConstraint && startsComparison(condition[i+len(OrOperator):]):
+			return i
+		}
+	}
+	return len(condition)
+}
+
+// startsComparison returns whether what follows a logical operator is a comparison, a group or a negation rather
+// than the rest of a value.
+//
+// An operator followed by nothing is treated as a logical operator, so that it is reported as a syntax error.
+func startsComparison(rest string) bool {
+	rest = strings.TrimLeft(rest, " ")
+	if len(rest) == 0 || rest[0] == '(' || strings.HasPrefix(rest, VersionPlaceholder) {
+		return true
+	}
+	if strings.HasPrefix(rest, NotOperator) && !strings.HasPrefix(rest, "!=") {
+		return true
+	}
+	// Comparisons start with a placeholder or with a function of a placeholder, even if they are incomplete
+	for placeholder := range placeholderResolvers {
+		if strings.HasPrefix(rest, placeholder) {
+			return true
+		}
+	}
+	for _, prefix := range []string{LengthFunctionPrefix, HasFunctionPrefix, LowerFunctionPrefix, UpperFunctionPrefix, TrimFunctionPrefix} {
+		if strings.HasPrefix(rest, prefix) {
+			return true
+		}
+	}
+	// The comparison ends at the next logical operator, if any
+	if end := strings.IndexAny(rest, "&|"); end >= 0 {
+		rest = rest[:end]
+	}
+	for _, comparator := range comparators {
+		if strings.Contains(rest, " "+comparator+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// conditionExpression is a node of the syntax tree of a compiled Condition
+type conditionExpression interface {
+	// evaluate returns whether the expression is met by the result, as well as how the expression should be displayed
+	evaluate(result *Result, dontResolveFailedConditions bool) (bool, string)
+
+	// String returns the expression without any of its placeholders resolved
+	String() string
+}
+
+// notExpression negates its operand
+type notExpression struct {
+	operand conditionExpression
+}
+
+func (e *notExpression) evaluate(result *Result, dontResolveFailedConditions bool) (bool, string) {
+	success, display := e.operand.evaluate(result, dontResolveFailedConditions)
+	return !success, NotOperator + display
+}
+
+func (e *notExpression) String() string {
+	return NotOperator + e.operand.String()
+}
+
+// groupExpression is an expression between parentheses
+type groupExpression struct {
+	expression conditionExpression
+}
+
+func (e *groupExpression) evaluate(result *Result, dontResolveFailedConditions bool) (bool, string) {
+	success, display := e.expression.evaluate(result, dontResolveFailedConditions)
+	return success, "(" + display + ")"
+}
+
+func (e *groupExpression) String() string {
+	return "(" + e.expression.String() + ")"
+}
+
+// logicalExpression is the conjunction or the disjunction of two expressions.
+//
+// The right expression is only evaluated if the left one does not determine the outcome already.
+type logicalExpression struct {
+	operator    string
+	left, right conditionExpression
+}
+
+func (e *logicalExpression) evaluate(result *Result, dontResolveFailedConditions bool) (bool, string) {
+	errorsBeforeLeft := len(result.Errors)
+	success, display := e.left.evaluate(result, dontResolveFailedConditions)
+	if (e.operator == AndOperator && !success) || (e.operator == OrOperator && success) {
+		return success, display + " " + e.operator + " " + e.right.String()
+	}
+	errorsBeforeRight := len(result.Errors)
+	success, rightDisplay := e.right.evaluate(result, dontResolveFailedConditions)
+	if e.operator == OrOperator && success {
+		// The left expression failed without affecting the outcome, so its errors aren't errors of the result
+		result.Errors = append(result.Errors[:errorsBeforeLeft], result.Errors[errorsBeforeRight:]...)
+	}
+	return success, display + " " + e.operator + " " + rightDisplay
+}
+
+func (e *logicalExpression) String() string {
+	return e.left.String() + " " + e.operator + " " + e.right.String()
+}
+
+// validateConditionExpression validates each of the comparisons of a compiled Condition
+func validateConditionExpression(expression conditionExpression) error {
+	switch e := expression.(type) {
+	case *notExpression:
+		return validateConditionExpression(e.operand)
+	case *groupExpression:
+		return validateConditionExpression(e.expression)
+	case *logicalExpression:
+		if err := validateConditionExpression(e.left); err != nil {
+			return err
+		}
+		return validateConditionExpression(e.right)
+	case *comparisonExpression:
+		return e.validate()
+	}
+	return nil
+}
+
+// conditionParser builds the syntax tree of a Condition from its tokens.
+//
+// From lowest to highest, the precedence of the operators is ||, && and !
+type conditionParser struct {
+	condition string
+	tokens    []conditionToken
+	position  int
+}
+
+// parseCondition returns the syntax tree of a condition, with each of its comparisons compiled
+func parseCondition(condition string) (conditionExpression, error) {
+	tokens, err := tokenizeCondition(condition)
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition: %s: %w", condition, err)
+	}
+	parser := &conditionParser{condition: condition, tokens: tokens}
+	expression, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if parser.position < len(parser.tokens) {
+		token := parser.tokens[parser.position]
+		return nil, parser.errorf("unexpected '%s' at position %d", token.value, token.position)
+	}
+	return expression, nil
+}
+
+func (p *conditionParser) parseOr() (conditionExpression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume(orToken) {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpression{operator: OrOperator, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *conditionParser) parseAnd() (conditionExpression, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume(andToken) {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpression{operator: AndOperator, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *conditionParser) parseUnary() (conditionExpression, error) {
+	if p.position >= len(p.tokens) {
+		return nil, p.errorf("unexpected end of condition")
+	}
+	token := p.tokens[p.position]
+	p.position++
+	switch token.tokenType {
+	case notToken:
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpression{operand: operand}, nil
+	case openingParenthesisToken:
+		expression, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume(closingParenthesisToken) {
+			return nil, p.errorf("missing ')' for '(' at position %d", token.position)
+		}
+		return &groupExpression{expression: expression}, nil
+	case comparisonToken:
+		return compileComparison(token.value)
+	}
+	return nil, p.errorf("unexpected '%s' at position %d", token.value, token.position)
+}
+
+// consume moves to the next token if the current one is of the given type
+func (p *conditionParser) consume(tokenType conditionTokenType) bool {
+	if p.position < len(p.tokens) && p.tokens[p.position].tokenType == tokenType {
+		p.position++
+		return true
+	}
+	return false
+}
+
+// errorf returns a syntax error of the condition being parsed
+func (p *conditionParser) errorf(format string, a ...interface{}) error {
+	return fmt.Errorf("invalid condition: %s: %s", p.condition, fmt.Sprintf(format, a...))
+}
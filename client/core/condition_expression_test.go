@@ -0,0 +1,73 @@
+package core
+
+import (
+	"testing"
+)
+
+func TestParseCondition(t *testing.T) {
+	scenarios := []struct {
+		condition      string
+		expectedString string
+		expectedError  string
+	}{
+		{condition: "[STATUS] == 200", expectedString: "[STATUS] == 200"},
+		{condition: "  [STATUS] == 200  ", expectedString: "[STATUS] == 200"},
+		{condition: "[STATUS] == any(200, 201)", expectedString: "[STATUS] == any(200, 201)"},
+		{condition: "len([BODY].data) > 0 && has([BODY].errors) == false", expectedString: "len([BODY].data) > 0 && has([BODY].errors) == false"},
+		{condition: "[BODY] == pat(*(a || b)*)", expectedString: "[BODY] == pat(*(a || b)*)"},
+		{condition: "[STATUS] != 200", expectedString: "[STATUS] != 200"},
+		{condition: "![CONNECTED] == false", expectedString: "![CONNECTED] == false"},
+		{condition: "!([STATUS] == 500)", expectedString: "!([STATUS] == 500)"},
+		{condition: "[STATUS] == 200 || ([STATUS] == 503&&[BODY] == maintenance)", expectedString: "[STATUS] == 200 || ([STATUS] == 503 && [BODY] == maintenance)"},
+		{condition: "[VERSION] ^1.2 || ^2.0", expectedString: "[VERSION] ^1.2 || ^2.0"},
+		{condition: `[BODY] == re(^\($)&&[STATUS] == 200`, expectedString: `[BODY] == re(^\($) && [STATUS] == 200`},
+		{condition: "", expectedError: "unexpected end of condition"},
+		{condition: "[STATUS] == 200 &&", expectedError: "unexpected end of condition"},
+		{condition: "([STATUS] == 200", expectedError: "missing ')' for '(' at position 0"},
+		{condition: "[STATUS] == 200)", expectedString: "[STATUS] == 200)"},
+		{condition: "[BODY] == :)", expectedString: "[BODY] == :)"},
+		{condition: "[BODY] == a|b||c", expectedString: "[BODY] == a|b||c"},
+		{condition: "[BODY] == a&&b", expectedString: "[BODY] == a&&b"},
+		{condition: "[BODY] == a|b||c || [STATUS] == 200", expectedString: "[BODY] == a|b||c || [STATUS] == 200"},
+		{condition: "[STATUS] == 200 || len([BODY].data) > 0", expectedString: "[STATUS] == 200 || len([BODY].data) > 0"},
+		{condition: "([STATUS] == 200))", expectedError: "unexpected ')' at position 17"},
+		{condition: "[STATUS] == 200 && ()", expectedError: "expected a comparison at position 20"},
+		{condition: "!", expectedError: "unexpected end of condition"},
+		{condition: "[VERSION] potato", expectedError: "improper constraint: potato"},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.condition, func(t *testing.T) {
+			expression, err := parseCondition(scenario.condition)
+			if len(scenario.expectedError) > 0 {
+				if expectedError := "invalid condition: " + scenario.condition + ": " + scenario.expectedError; err == nil || err.Error() != expectedError {
+					t.Errorf("expected error %q, got %v", expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expression.String() != scenario.expectedString {
+				t.Errorf("expected %q, got %q", scenario.expectedString, expression.String())
+			}
+		})
+	}
+}
+
+func TestParseConditionPrecedence(t *testing.T) {
+	expression, err := parseCondition("a == a || !b == b && c == c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	or, ok := expression.(*logicalExpression)
+	if !ok || or.operator != OrOperator {
+		t.Fatalf("expected || to be the root of the syntax tree, got %#v", expression)
+	}
+	and, ok := or.right.(*logicalExpression)
+	if !ok || and.operator != AndOperator {
+		t.Fatalf("expected && to bind tighter than ||, got %#v", or.right)
+	}
+	if _, ok := and.left.(*notExpression); !ok {
+		t.Errorf("expected ! to bind tighter than &&, got %#v", and.left)
+	}
+}
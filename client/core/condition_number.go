@@ -0,0 +1,93 @@
+package core
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+)
+
+// conditionNumber is the numerical value of an operand compared with <, <=, > or >=
+type conditionNumber struct {
+	integer   int64
+	float     float64
+	isInteger bool
+
+	// decimal is the number as it was written, if it is not an integer. It is used to compare numbers exactly when
+	// float64 can't tell them apart.
+	decimal string
+}
+
+// toNumber converts a value to a number so that it can be compared with <, <=, > and >=.
+//
+// Durations are converted to milliseconds. Values that aren't numbers, such as the value of a missing JSON path, can't
+// be compared, in which case the second value returned is false.
+func toNumber(value string) (conditionNumber, bool) {
+	if duration, err := time.ParseDuration(value); err == nil {
+		// If the string is a duration, convert it to milliseconds
+		if duration%time.Millisecond == 0 {
+			return conditionNumber{integer: duration.Milliseconds(), isInteger: true}, true
+		}
+		return conditionNumber{float: float64(duration) / float64(time.Millisecond)}, true
+	} else if number, err := strconv.ParseInt(value, 0, 64); err == nil {
+		return conditionNumber{integer: number, isInteger: true}, true
+	} else if f, err := strconv.ParseFloat(value, 64); err == nil && !math.IsNaN(f) {
+		return conditionNumber{float: f, decimal: value}, true
+	}
+	return conditionNumber{}, false
+}
+
+// compare returns -1, 0 or +1 depending on whether the number is lower than, equal to or greater than the other.
+//
+// Integers are compared as int64 so that they don't lose precision, and other numbers as float64, unless float64 can't
+// tell them apart, in which case their decimal representations are compared exactly.
+func (n conditionNumber) compare(other conditionNumber) int {
+	if n.isInteger && other.isInteger {
+		switch {
+		case n.integer < other.integer:
+			return -1
+		case n.integer > other.integer:
+			return 1
+		}
+		return 0
+	}
+	first, second := n.toFloat(), other.toFloat()
+	switch {
+	case first < second:
+		return -1
+	case first > second:
+		return 1
+	}
+	if firstRat, ok := n.toRat(); ok {
+		if secondRat, ok := other.toRat(); ok {
+			return firstRat.Cmp(secondRat)
+		}
+	}
+	return 0
+}
+
+func (n conditionNumber) toFloat() float64 {
+	if n.isInteger {
+		return float64(n.integer)
+	}
+	return n.float
+}
+
+// toRat returns the exact value of the number, if it has one
+func (n conditionNumber) toRat() (*big.Rat, bool) {
+	if n.isInteger {
+		return new(big.Rat).SetInt64(n.integer), true
+	}
+	if len(n.decimal) == 0 {
+		return nil, false
+	}
+	return new(big.Rat).SetString(n.decimal)
+}
+
+// String returns the number the way it is displayed in the result of a condition
+func (n conditionNumber) String() string {
+	if n.isInteger {
+		return strconv.FormatInt(n.integer, 10)
+	}
+	return strconv.FormatFloat(n.float, 'f', -1, 64)
+}
@@ -0,0 +1,66 @@
+package core
+
+import (
+	"testing"
+)
+
+func TestToNumber(t *testing.T) {
+	scenarios := []struct {
+		value    string
+		expected string
+		isNumber bool
+	}{
+		{value: "200", expected: "200", isNumber: true},
+		{value: "0x1F", expected: "31", isNumber: true},
+		{value: "0.8", expected: "0.8", isNumber: true},
+		{value: "123.40000000000005", expected: "123.40000000000005", isNumber: true},
+		{value: "1e3", expected: "1000", isNumber: true},
+		{value: "48h", expected: "172800000", isNumber: true},
+		{value: "1.5ms", expected: "1.5", isNumber: true},
+		{value: "0s", expected: "0", isNumber: true},
+		{value: "NaN"},
+		{value: "potato"},
+		{value: ""},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.value, func(t *testing.T) {
+			number, isNumber := toNumber(scenario.value)
+			if isNumber != scenario.isNumber {
+				t.Fatalf("expected isNumber to be %v, got %v", scenario.isNumber, isNumber)
+			}
+			if isNumber && number.String() != scenario.expected {
+				t.Errorf("expected %s, got %s", scenario.expected, number.String())
+			}
+		})
+	}
+}
+
+func TestConditionNumber_compare(t *testing.T) {
+	scenarios := []struct {
+		first, second string
+		expected      int
+	}{
+		{first: "1", second: "2", expected: -1},
+		{first: "0.93", second: "0.8", expected: 1},
+		{first: "0", second: "0.8", expected: -1},
+		{first: "99.949", second: "99.95", expected: -1},
+		{first: "99.95", second: "99.95", expected: 0},
+		{first: "100", second: "99.95", expected: 1},
+		{first: "9007199254740993", second: "9007199254740992", expected: 1},
+		{first: "0.30000000000000000001", second: "0.3", expected: 1},
+		{first: "9007199254740993", second: "9007199254740992.5", expected: 1},
+		{first: "1s", second: "999.5ms", expected: 1},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.first+"-"+scenario.second, func(t *testing.T) {
+			first, _ := toNumber(scenario.first)
+			second, _ := toNumber(scenario.second)
+			if comparison := first.compare(second); comparison != scenario.expected {
+				t.Errorf("expected %d, got %d", scenario.expected, comparison)
+			}
+			if comparison := second.compare(first); comparison != -scenario.expected {
+				t.Errorf("expected %d when swapping the numbers, got %d", -scenario.expected, comparison)
+			}
+		})
+	}
+}
@@ -42,6 +42,35 @@ func TestCondition_Validate(t *testing.T) {
 		{condition: "[STATUS] = = 201", expectedErr: errors.New("invalid condition: [STATUS] = = 201")},
 		{condition: "[STATUS] ==", expectedErr: errors.New("invalid condition: [STATUS] ==")},
 		{condition: "[STATUS]", expectedErr: errors.New("invalid condition: [STATUS]")},
+		{condition: "[STATUS] == 200 || ([STATUS] == 503 && [BODY].status == maintenance)", expectedErr: nil},
+		{condition: "!([STATUS] == 500)", expectedErr: nil},
+		{condition: "[BODY] == re(^(ok|OK)$)", expectedErr: nil},
+		{condition: "[BODY] == re(a{2,1})", expectedErr: errors.New("invalid condition: [BODY] == re(a{2,1}): error parsing regexp: invalid repeat count: `{2,1}`")},
+		{condition: "[STATUS] < re(^2)", expectedErr: errors.New("invalid condition: [STATUS] < re(^2): re() can only be used with == and !=")},
+		{condition: "[BODY] == contains(maintenance)", expectedErr: nil},
+		{condition: "[BANNER] != startsWith(SSH-1.)", expectedErr: nil},
+		{condition: "lower([BODY].status) == endsWith(ok)", expectedErr: nil},
+		{condition: "len([DNS_RCODE]) > 0", expectedErr: nil},
+		{condition: "[BODY] >= contains(maintenance)", expectedErr: errors.New("invalid condition: [BODY] >= contains(maintenance): contains() can only be used with == and !=")},
+		{condition: "[BODY] == endsWith()", expectedErr: errors.New("invalid condition: [BODY] == endsWith(): endsWith() requires a value")},
+		{condition: "upper(ok) == OK", expectedErr: errors.New("invalid condition: upper(ok) == OK: upper() must be used with a placeholder")},
+		{condition: "len(trim(abc)) == 3", expectedErr: errors.New("invalid condition: len(trim(abc)) == 3: trim() must be used with a placeholder")},
+		{condition: "startsWith(a) == re(b)", expectedErr: errors.New("invalid condition: startsWith(a) == re(b): only one of the values can be a function matching the other")},
+		{condition: "[RESPONSE_TIME] < potato", expectedErr: errors.New("invalid condition: [RESPONSE_TIME] < potato: potato is not a number")},
+		{condition: "[STATUS] == 200 || !(abc >= [BODY].count)", expectedErr: errors.New("invalid condition: abc >= [BODY].count: abc is not a number")},
+		{condition: "[CERTIFICATE_EXPIRATION] > 48h", expectedErr: nil},
+		{condition: "foo([BODY].name) == bar", expectedErr: errors.New("invalid condition: foo([BODY].name) == bar: unknown function foo()")},
+		{condition: "[STATUS] == size([status])", expectedErr: errors.New("invalid condition: [STATUS] == size([status]): unknown function size()")},
+		{condition: "[BODY].name == hello(world)", expectedErr: nil},
+		{condition: "has([BANNER]) == true", expectedErr: errors.New("invalid condition: has([BANNER]) == true: has() only works with the [BODY] placeholder")},
+		{condition: "[STATUS] == any([BODY].status, 200)", expectedErr: errors.New("invalid condition: [STATUS] == any([BODY].status, 200): any() doesn't work with placeholders")},
+		{condition: "pat([IP]) == 127.0.0.1", expectedErr: errors.New("invalid condition: pat([IP]) == 127.0.0.1: pat() doesn't work with placeholders")},
+		{condition: "[BANNER] == contains([ip])", expectedErr: errors.New("invalid condition: [BANNER] == contains([ip]): contains() doesn't work with placeholders")},
+		{condition: "has([BODY].errors) == false", expectedErr: nil},
+		{condition: "len([BODY].data) > 0", expectedErr: nil},
+		{condition: "[STATUS] == 200 || [STATUS]", expectedErr: errors.New("invalid condition: [STATUS]")},
+		{condition: "([STATUS] == 200", expectedErr: errors.New("invalid condition: ([STATUS] == 200: missing ')' for '(' at position 0")},
+		{condition: "[STATUS] == 200 &&", expectedErr: errors.New("invalid condition: [STATUS] == 200 &&: unexpected end of condition")},
 		// FIXME: Should return an error, but doesn't because jsonpath isn't evaluated due to body being empty in Condition.Validate()
 		//{condition: "len([BODY].users == 100", expectedErr: nil},
 	}
@@ -84,6 +113,20 @@ func TestCondition_evaluate(t *testing.T) {
 			ExpectedSuccess: false,
 			ExpectedOutput:  "[STATUS] (500) == 200",
 		},
+		{
+			Name:            "body-with-closing-parenthesis",
+			Condition:       Condition("[BODY] == :)"),
+			Result:          &Result{Body: []byte(":)")},
+			ExpectedSuccess: true,
+			ExpectedOutput:  "[BODY] == :)",
+		},
+		{
+			Name:            "body-with-pipes",
+			Condition:       Condition("[BODY] == a|b||c"),
+			Result:          &Result{Body: []byte("a|b||c")},
+			ExpectedSuccess: true,
+			ExpectedOutput:  "[BODY] == a|b||c",
+		},
 		{
 			Name:            "status-using-less-than",
 			Condition:       Condition("[STATUS] < 300"),
@@ -119,6 +162,20 @@ func TestCondition_evaluate(t *testing.T) {
 			ExpectedSuccess: false,
 			ExpectedOutput:  "[RESPONSE_TIME] (50) < potato (0)", // Non-numerical values automatically resolve to 0
 		},
+		{
+			Name:            "body-missing-path-using-less-than",
+			Condition:       Condition("[BODY].missing < 0.8"),
+			Result:          &Result{Body: []byte(`{"ratio": 0.5}`)},
+			ExpectedSuccess: false,
+			ExpectedOutput:  "[BODY].missing (INVALID) < 0.8",
+		},
+		{
+			Name:            "body-string-using-less-than",
+			Condition:       Condition("[BODY].name < 1"),
+			Result:          &Result{Body: []byte(`{"name": "abc"}`)},
+			ExpectedSuccess: false,
+			ExpectedOutput:  "[BODY].name (abc) < 1",
+		},
 		{
 			Name:            "response-time-using-greater-than",
 			Condition:       Condition("[RESPONSE_TIME] > 500"),
@@ -434,6 +491,104 @@ func TestCondition_evaluate(t *testing.T) {
 			ExpectedSuccess: false,
 			ExpectedOutput:  "[CONNECTED] (false) == true",
 		},
+		{
+			Name:            "dns-ttl",
+			Condition:       Condition("[DNS_TTL] >= 300"),
+			Result:          &Result{DNSTTL: 3600},
+			ExpectedSuccess: true,
+			ExpectedOutput:  "[DNS_TTL] >= 300",
+		},
+		{
+			Name:            "dns-ttl-failure",
+			Condition:       Condition("[DNS_TTL] >= 300"),
+			Result:          &Result{DNSTTL: 60},
+			ExpectedSuccess: false,
+			ExpectedOutput:  "[DNS_TTL] (60) >= 300",
+		},
+		{
+			Name:            "dns-authoritative",
+			Condition:       Condition("[DNS_AUTHORITATIVE] == true"),
+			Result:          &Result{DNSAuthoritative: false},
+			ExpectedSuccess: false,
+			ExpectedOutput:  "[DNS_AUTHORITATIVE] (false) == true",
+		},
+		{
+			Name:            "dnssec",
+			Condition:       Condition("[DNSSEC] == secure"),
+			Result:          &Result{DNSSEC: DNSSECBogus},
+			ExpectedSuccess: false,
+			ExpectedOutput:  "[DNSSEC] (bogus) == secure",
+		},
+		{
+			Name:            "dnssec-expiration",
+			Condition:       Condition("[DNSSEC_EXPIRATION] > 24h"),
+			Result:          &Result{DNSSEC: DNSSECSecure, DNSSECExpiration: 72 * time.Hour},
+			ExpectedSuccess: true,
+			ExpectedOutput:  "[DNSSEC_EXPIRATION] > 24h",
+		},
+		{
+			Name:            "dns-body-length",
+			Condition:       Condition("len([BODY]) == 2"),
+			Result:          &Result{Body: []byte(`["93.184.216.34","93.184.216.35"]`)},
+			ExpectedSuccess: true,
+			ExpectedOutput:  "len([BODY]) == 2",
+		},
+		{
+			Name:            "dns-body-index",
+			Condition:       Condition("[BODY][1] == 93.184.216.35"),
+			Result:          &Result{Body: []byte(`["93.184.216.34","93.184.216.35"]`)},
+			ExpectedSuccess: true,
+			ExpectedOutput:  "[BODY][1] == 93.184.216.35",
+		},
+		{
+			Name:            "grpc-status",
+			Condition:       Condition("[GRPC_STATUS] == SERVING"),
+			Result:          &Result{GRPCStatus: "SERVING"},
+			ExpectedSuccess: true,
+			ExpectedOutput:  "[GRPC_STATUS] == SERVING",
+		},
+		{
+			Name:            "grpc-status-failure",
+			Condition:       Condition("[GRPC_STATUS] == SERVING"),
+			Result:          &Result{GRPCStatus: "NOT_SERVING"},
+			ExpectedSuccess: false,
+			ExpectedOutput:  "[GRPC_STATUS] (NOT_SERVING) == SERVING",
+		},
+		{
+			Name:            "banner",
+			Condition:       Condition("[BANNER] == pat(*OpenSSH*)"),
+			Result:          &Result{Banner: "SSH-2.0-OpenSSH_9.6"},
+			ExpectedSuccess: true,
+			ExpectedOutput:  "[BANNER] == pat(*OpenSSH*)",
+		},
+		{
+			Name:            "packet-loss",
+			Condition:       Condition("[PACKET_LOSS] < 50"),
+			Result:          &Result{PacketLoss: 33.333333333333336},
+			ExpectedSuccess: true,
+			ExpectedOutput:  "[PACKET_LOSS] < 50",
+		},
+		{
+			Name:            "packet-loss-failure",
+			Condition:       Condition("[PACKET_LOSS] == 0"),
+			Result:          &Result{PacketLoss: 100},
+			ExpectedSuccess: false,
+			ExpectedOutput:  "[PACKET_LOSS] (100) == 0",
+		},
+		{
+			Name:            "jitter",
+			Condition:       Condition("[JITTER] < 10"),
+			Result:          &Result{Jitter: 5 * time.Millisecond},
+			ExpectedSuccess: true,
+			ExpectedOutput:  "[JITTER] < 10",
+		},
+		{
+			Name:            "jitter-failure",
+			Condition:       Condition("[JITTER] < 10"),
+			Result:          &Result{Jitter: 25 * time.Millisecond},
+			ExpectedSuccess: false,
+			ExpectedOutput:  "[JITTER] (25) < 10",
+		},
 		{
 			Name:            "certificate-expiration-not-set",
 			Condition:       Condition("[CERTIFICATE_EXPIRATION] == 0"),
@@ -763,6 +918,239 @@ func TestCondition_evaluate(t *testing.T) {
 			ExpectedSuccess:             false,
 			ExpectedOutput:              "[VERSION] (2.0.0) != ^1.2.3",
 		},
+		{
+			Name:            "version-match-one-of-ranges",
+			Condition:       Condition("[VERSION] ^1.2 || ^2.0"),
+			Result:          &Result{Body: []byte("{\"data\":\"2.0.1\"}")},
+			ExpectedSuccess: true,
+			ExpectedOutput:  "[VERSION] ^1.2 || ^2.0",
+		},
+		{
+			Name:            "float-using-less-than",
+			Condition:       Condition("[BODY].load < 0.8"),
+			Result:          &Result{Body: []byte(`{"load": 0.42}`)},
+			ExpectedSuccess: true,
+			ExpectedOutput:  "[BODY].load < 0.8",
+		},
+		{
+			Name:            "float-using-less-than-failure",
+			Condition:       Condition("[BODY].load < 0.8"),
+			Result:          &Result{Body: []byte(`{"load": 0.93}`)},
+			ExpectedSuccess: false,
+			ExpectedOutput:  "[BODY].load (0.93) < 0.8",
+		},
+		{
+			Name:            "float-using-greater-than-or-equal-failure",
+			Condition:       Condition("[BODY].ratio >= 99.95"),
+			Result:          &Result{Body: []byte(`{"ratio": 99.949}`)},
+			ExpectedSuccess: false,
+			ExpectedOutput:  "[BODY].ratio (99.949) >= 99.95",
+		},
+		{
+			Name:            "packet-loss-using-greater-than-failure",
+			Condition:       Condition("[PACKET_LOSS] > 33.4"),
+			Result:          &Result{PacketLoss: 33.333333333333336},
+			ExpectedSuccess: false,
+			ExpectedOutput:  "[PACKET_LOSS] (33.333333333333336) > 33.4",
+		},
+		{
+			Name:            "integer-beyond-float-precision",
+			Condition:       Condition("[BODY].id > 9007199254740992"),
+			Result:          &Result{Body: []byte(`{"id": 9007199254740993}`)},
+			ExpectedSuccess: true,
+			ExpectedOutput:  "[BODY].id > 9007199254740992",
+		},
+		{
+			Name:            "regex",
+			Condition:       Condition(`[BANNER] == re(^SSH-2\.0-OpenSSH_(?P<version>[\d.]+))`),
+			Result:          &Result{Banner: "SSH-2.0-OpenSSH_9.6"},
+			ExpectedSuccess: true,
+			ExpectedOutput:  `[BANNER] == re(^SSH-2\.0-OpenSSH_(?P<version>[\d.]+)) (version=9.6)`,
+		},
+		{
+			Name:            "regex-with-alternation",
+			Condition:       Condition("[STATUS] == re(^(200|204)$)"),
+			Result:          &Result{HTTPStatus: 204},
+			ExpectedSuccess: true,
+			ExpectedOutput:  "[STATUS] == re(^(200|204)$)",
+		},
+		{
+			Name:            "regex-failure",
+			Condition:       Condition("re(^(200|204)$) == [STATUS]"),
+			Result:          &Result{HTTPStatus: 201},
+			ExpectedSuccess: false,
+			ExpectedOutput:  "re(^(200|204)$) == [STATUS] (201)",
+		},
+		{
+			Name:            "regex-failure-with-long-body",
+			Condition:       Condition("[BODY] == re((?m)^status: ok$)"),
+			Result:          &Result{Body: []byte("<!DOCTYPE html>\n<html>\nstatus: degraded\n</html>")},
+			ExpectedSuccess: false,
+			ExpectedOutput:  "[BODY] (<!DOCTYPE html>\n<html>\nst...(truncated)) == re((?m)^status: ok$)",
+		},
+		{
+			Name:            "regex-not-equal-failure",
+			Condition:       Condition("[BODY].message != re((?i)(?P<kind>error|exception))"),
+			Result:          &Result{Body: []byte(`{"message": "Internal Error"}`)},
+			ExpectedSuccess: false,
+			ExpectedOutput:  "[BODY].message (Internal Error) != re((?i)(?P<kind>error|exception)) (kind=Error)",
+		},
+		{
+			Name:                        "regex-not-equal-failure-without-resolving",
+			Condition:                   Condition("[BODY].message != re((?i)(?P<kind>error|exception))"),
+			Result:                      &Result{Body: []byte(`{"message": "Internal Error"}`)},
+			DontResolveFailedConditions: true,
+			ExpectedSuccess:             false,
+			ExpectedOutput:              "[BODY].message != re((?i)(?P<kind>error|exception))",
+		},
+		{
+			Name:            "contains",
+			Condition:       Condition("[BODY] == contains(<h1>Welcome</h1>)"),
+			Result:          &Result{Body: []byte("<html>\n<body>\n<h1>Welcome</h1>\n</body>\n</html>")},
+			ExpectedSuccess: true,
+			ExpectedOutput:  "[BODY] == contains(<h1>Welcome</h1>)",
+		},
+		{
+			Name:            "contains-failure",
+			Condition:       Condition("[BODY] == contains(<h1>Welcome</h1>)"),
+			Result:          &Result{Body: []byte("<html>\n<body>\n<h1>Under maintenance</h1>\n</body>\n</html>")},
+			ExpectedSuccess: false,
+			ExpectedOutput:  "[BODY] (<html>\n<body>\n<h1>Under m...(truncated)) == contains(<h1>Welcome</h1>)",
+		},
+		{
+			Name:            "not-contains-failure",
+			Condition:       Condition("[BODY].message != contains(error)"),
+			Result:          &Result{Body: []byte(`{"message": "internal error"}`)},
+			ExpectedSuccess: false,
+			ExpectedOutput:  "[BODY].message (internal error) != contains(error)",
+		},
+		{
+			Name:            "starts-with",
+			Condition:       Condition("[BANNER] == startsWith(SSH-2.0-)"),
+			Result:          &Result{Banner: "SSH-2.0-OpenSSH_9.6"},
+			ExpectedSuccess: true,
+			ExpectedOutput:  "[BANNER] == startsWith(SSH-2.0-)",
+		},
+		{
+			Name:            "ends-with-failure",
+			Condition:       Condition("endsWith(@example.org) == [BODY].email"),
+			Result:          &Result{Body: []byte(`{"email": "john@example.com"}`)},
+			ExpectedSuccess: false,
+			ExpectedOutput:  "endsWith(@example.org) == [BODY].email (john@example.com)",
+		},
+		{
+			Name:            "lower",
+			Condition:       Condition("lower([BODY].status) == ok"),
+			Result:          &Result{Body: []byte(`{"status": "OK"}`)},
+			ExpectedSuccess: true,
+			ExpectedOutput:  "lower([BODY].status) == ok",
+		},
+		{
+			Name:            "upper-failure",
+			Condition:       Condition("upper([GRPC_STATUS]) == SERVING"),
+			Result:          &Result{GRPCStatus: "not_serving"},
+			ExpectedSuccess: false,
+			ExpectedOutput:  "upper([GRPC_STATUS]) (NOT_SERVING) == SERVING",
+		},
+		{
+			Name:            "trim",
+			Condition:       Condition("trim([BODY].status) == ok"),
+			Result:          &Result{Body: []byte(`{"status": "  ok\n"}`)},
+			ExpectedSuccess: true,
+			ExpectedOutput:  "trim([BODY].status) == ok",
+		},
+		{
+			Name:            "len-of-placeholder",
+			Condition:       Condition("len([BANNER]) < 16"),
+			Result:          &Result{Banner: "SSH-2.0-OpenSSH_9.6"},
+			ExpectedSuccess: false,
+			ExpectedOutput:  "len([BANNER]) (19) < 16",
+		},
+		{
+			Name:            "len-of-function",
+			Condition:       Condition("len(trim([BODY].name)) == 4"),
+			Result:          &Result{Body: []byte(`{"name": " john "}`)},
+			ExpectedSuccess: true,
+			ExpectedOutput:  "len(trim([BODY].name)) == 4",
+		},
+		{
+			Name:            "lower-with-invalid-path",
+			Condition:       Condition("lower([BODY].user.name) == john"),
+			Result:          &Result{Body: []byte(`{"user": "John"}`)},
+			ExpectedSuccess: false,
+			ExpectedOutput:  "[BODY].user.name (INVALID) == john",
+		},
+		{
+			Name:            "or",
+			Condition:       Condition("[STATUS] == 200 || [STATUS] == 503"),
+			Result:          &Result{HTTPStatus: 503},
+			ExpectedSuccess: true,
+			ExpectedOutput:  "[STATUS] (503) == 200 || [STATUS] == 503",
+		},
+		{
+			Name:            "or-short-circuit",
+			Condition:       Condition("[STATUS] == 200 || [STATUS] == 503"),
+			Result:          &Result{HTTPStatus: 200},
+			ExpectedSuccess: true,
+			ExpectedOutput:  "[STATUS] == 200 || [STATUS] == 503",
+		},
+		{
+			Name:            "and-failure",
+			Condition:       Condition("[STATUS] == 200 && [RESPONSE_TIME] < 500"),
+			Result:          &Result{HTTPStatus: 200, Duration: time.Second},
+			ExpectedSuccess: false,
+			ExpectedOutput:  "[STATUS] == 200 && [RESPONSE_TIME] (1000) < 500",
+		},
+		{
+			Name:            "and-short-circuit",
+			Condition:       Condition("[STATUS] == 200 && [RESPONSE_TIME] < 500"),
+			Result:          &Result{HTTPStatus: 500, Duration: time.Second},
+			ExpectedSuccess: false,
+			ExpectedOutput:  "[STATUS] (500) == 200 && [RESPONSE_TIME] < 500",
+		},
+		{
+			Name:            "or-with-group",
+			Condition:       Condition("[STATUS] == 200 || ([STATUS] == 503 && [BODY].status == maintenance)"),
+			Result:          &Result{HTTPStatus: 503, Body: []byte(`{"status":"maintenance"}`)},
+			ExpectedSuccess: true,
+			ExpectedOutput:  "[STATUS] (503) == 200 || ([STATUS] == 503 && [BODY].status == maintenance)",
+		},
+		{
+			Name:            "or-with-group-failure",
+			Condition:       Condition("[STATUS] == 200 || ([STATUS] == 503 && [BODY].status == maintenance)"),
+			Result:          &Result{HTTPStatus: 503, Body: []byte(`{"status":"down"}`)},
+			ExpectedSuccess: false,
+			ExpectedOutput:  "[STATUS] (503) == 200 || ([STATUS] == 503 && [BODY].status (down) == maintenance)",
+		},
+		{
+			Name:                        "or-with-group-failure-without-resolving",
+			Condition:                   Condition("[STATUS] == 200 || ([STATUS] == 503 && [BODY].status == maintenance)"),
+			Result:                      &Result{HTTPStatus: 503, Body: []byte(`{"status":"down"}`)},
+			DontResolveFailedConditions: true,
+			ExpectedSuccess:             false,
+			ExpectedOutput:              "[STATUS] == 200 || ([STATUS] == 503 && [BODY].status == maintenance)",
+		},
+		{
+			Name:            "and-has-precedence-over-or",
+			Condition:       Condition("[STATUS] == 500 && [CONNECTED] == false || [CONNECTED] == true"),
+			Result:          &Result{HTTPStatus: 200, Connected: true},
+			ExpectedSuccess: true,
+			ExpectedOutput:  "[STATUS] (200) == 500 && [CONNECTED] == false || [CONNECTED] == true",
+		},
+		{
+			Name:            "not",
+			Condition:       Condition("!([STATUS] >= 500 || [CONNECTED] == false)"),
+			Result:          &Result{HTTPStatus: 404, Connected: true},
+			ExpectedSuccess: true,
+			ExpectedOutput:  "!([STATUS] (404) >= 500 || [CONNECTED] (true) == false)",
+		},
+		{
+			Name:            "not-failure",
+			Condition:       Condition("![BODY].name == pat(test*)"),
+			Result:          &Result{Body: []byte(`{"name":"test-1"}`)},
+			ExpectedSuccess: false,
+			ExpectedOutput:  "![BODY].name == pat(test*)",
+		},
 	}
 	for _, scenario := range scenarios {
 		t.Run(scenario.Name, func(t *testing.T) {
@@ -788,3 +1176,44 @@ func TestCondition_evaluateWithInvalidOperator(t *testing.T) {
 		t.Error("condition was invalid, result should've had an error")
 	}
 }
+
+func TestCondition_evaluateWithNonNumericalValueNotAffectingTheOutcome(t *testing.T) {
+	condition := Condition("[BODY].x > 5 || [STATUS] == 200")
+	result := &Result{HTTPStatus: 200, Body: []byte(`{"x": "abc"}`)}
+	if !condition.evaluate(result, false) {
+		t.Error("condition should've been successful")
+	}
+	if len(result.Errors) != 0 {
+		t.Errorf("expected no error, since the comparison that failed didn't affect the outcome, got %v", result.Errors)
+	}
+	result = &Result{HTTPStatus: 500, Body: []byte(`{"x": "abc"}`)}
+	if condition.evaluate(result, false) {
+		t.Error("condition should've failed")
+	}
+	if len(result.Errors) != 1 {
+		t.Errorf("expected the comparison that couldn't be evaluated to add an error, got %v", result.Errors)
+	}
+}
+
+func TestCondition_evaluateWithNonNumericalValue(t *testing.T) {
+	scenarios := []struct {
+		condition     Condition
+		body          string
+		expectedError string
+	}{
+		{condition: "[BODY].missing < 0.8", body: `{"ratio": 0.5}`, expectedError: `cannot evaluate [BODY].missing < 0.8: "[BODY].missing (INVALID)" is not a number`},
+		{condition: "[BODY].name < 1", body: `{"name": "abc"}`, expectedError: `cannot evaluate [BODY].name < 1: "abc" is not a number`},
+		{condition: "1 >= [BODY].name", body: `{"name": "abc"}`, expectedError: `cannot evaluate 1 >= [BODY].name: "abc" is not a number`},
+	}
+	for _, scenario := range scenarios {
+		t.Run(string(scenario.condition), func(t *testing.T) {
+			result := &Result{Body: []byte(scenario.body)}
+			if scenario.condition.evaluate(result, false) {
+				t.Error("condition compared a value that isn't a number, it should've failed")
+			}
+			if len(result.Errors) == 0 || result.Errors[len(result.Errors)-1] != scenario.expectedError {
+				t.Errorf("expected error %q, got %v", scenario.expectedError, result.Errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/redis/go-redis/v9"
+	"github.com/serverless-aliyun/func-status/client/util"
+)
+
+// ErrDatabaseWithInlineCredentials is the error with which Gatus will panic if a database endpoint has credentials in its url
+var ErrDatabaseWithInlineCredentials = errors.New("database endpoints must not have credentials in their url, use username-env and password-env instead")
+
+// Database is the configuration for a Endpoint of type POSTGRES, MYSQL or REDIS
+type Database struct {
+	// Query is run once connected and its scalar result is put in the body. If empty, the server is only pinged.
+	//
+	// For Redis, the query is a command whose arguments are separated by spaces, e.g. GET replication:lag
+	Query string `yaml:"query,omitempty"`
+
+	// UsernameEnv is the name of the environment variable holding the username
+	UsernameEnv string `yaml:"username-env,omitempty"`
+
+	// PasswordEnv is the name of the environment variable holding the password
+	PasswordEnv string `yaml:"password-env,omitempty"`
+}
+
+// probe connects to the database at the given url and runs the configured query, or a ping if there is none
+func (d *Database) probe(endpointType EndpointType, rawURL string, result *Result) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		result.AddError(err.Error())
+		return
+	}
+	if username, password := os.Getenv(d.UsernameEnv), os.Getenv(d.PasswordEnv); len(username) > 0 || len(password) > 0 {
+		u.User = url.UserPassword(username, password)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), util.ConnectionTimeout)
+	defer cancel()
+	switch endpointType {
+	case EndpointTypePOSTGRES:
+		d.querySQL(ctx, "pgx", u.String(), result)
+	case EndpointTypeMYSQL:
+		d.querySQL(ctx, "mysql", mysqlDSN(u), result)
+	default:
+		d.queryRedis(ctx, u.String(), result)
+	}
+}
+
+func (d *Database) querySQL(ctx context.Context, driverName, dsn string, result *Result) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		result.AddError(err.Error())
+		return
+	}
+	defer db.Close()
+	if err := db.PingContext(ctx); err != nil {
+		result.AddError(err.Error())
+		return
+	}
+	result.Connected = true
+	if len(d.Query) == 0 {
+		return
+	}
+	var value interface{}
+	if err := db.QueryRowContext(ctx, d.Query).Scan(&value); err != nil {
+		result.AddError(err.Error())
+		return
+	}
+	result.Body = formatScalar(value)
+}
+
+func (d *Database) queryRedis(ctx context.Context, dsn string, result *Result) {
+	options, err := redis.ParseURL(dsn)
+	if err != nil {
+		result.AddError(err.Error())
+		return
+	}
+	options.DialTimeout = util.ConnectionTimeout
+	client := redis.NewClient(options)
+	defer client.Close()
+	if err := client.Ping(ctx).Err(); err != nil {
+		result.AddError(err.Error())
+		return
+	}
+	result.Connected = true
+	if len(d.Query) == 0 {
+		return
+	}
+	var args []interface{}
+	for _, arg := range strings.Fields(d.Query) {
+		args = append(args, arg)
+	}
+	value, err := client.Do(ctx, args...).Result()
+	if err != nil {
+		result.AddError(err.Error())
+		return
+	}
+	result.Body = formatScalar(value)
+}
+
+// mysqlDSN converts a mysql://host:port/database?param=value url into the DSN expected by the MySQL driver
+func mysqlDSN(u *url.URL) string {
+	config := mysql.NewConfig()
+	config.Net = "tcp"
+	config.Addr = u.Host
+	config.DBName = strings.TrimPrefix(u.Path, "/")
+	config.Timeout = util.ConnectionTimeout
+	if u.User != nil {
+		config.User = u.User.Username()
+		config.Passwd, _ = u.User.Password()
+	}
+	for k, v := range u.Query() {
+		if config.Params == nil {
+			config.Params = make(map[string]string)
+		}
+		config.Params[k] = v[0]
+	}
+	return config.FormatDSN()
+}
+
+// formatScalar formats a value returned by a query so that conditions can be evaluated against it
+func formatScalar(value interface{}) []byte {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return v
+	case time.Time:
+		return []byte(v.Format(time.RFC3339Nano))
+	default:
+		return []byte(fmt.Sprint(v))
+	}
+}
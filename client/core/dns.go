@@ -1,11 +1,21 @@
 package core
 
 import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/miekg/dns"
+	"github.com/serverless-aliyun/func-status/client/util"
 )
 
 var (
@@ -14,10 +24,31 @@ var (
 
 	// ErrDNSWithInvalidQueryType is the error with which gatus will panic if a dns is configured with invalid query type
 	ErrDNSWithInvalidQueryType = errors.New("invalid query type")
+
+	// ErrDNSWithInvalidTransport is the error with which gatus will panic if a dns is configured with invalid transport
+	ErrDNSWithInvalidTransport = errors.New("invalid transport: must be one of udp, tcp, tls and https")
+
+	// ErrDNSWithInvalidAddress is the error with which gatus will panic if the url of a dns isn't in the format <host>[:<port>]
+	ErrDNSWithInvalidAddress = errors.New("invalid DNS server address: must be in the format <host>[:<port>]")
+
+	// ErrDNSOverHTTPSWithInvalidURL is the error with which gatus will panic if a dns with the https transport doesn't
+	// have an https:// url
+	ErrDNSOverHTTPSWithInvalidURL = errors.New("invalid DNS-over-HTTPS resolver: url must start with https://")
 )
 
 const (
-	dnsPort = 53
+	DNSTransportUDP   = "udp"
+	DNSTransportTCP   = "tcp"
+	DNSTransportTLS   = "tls"
+	DNSTransportHTTPS = "https"
+
+	dnsPort         = 53
+	dnsOverTLSPort  = 853
+	dnsMessageMedia = "application/dns-message"
+
+	// dnsOverHTTPSIdleConnectionTimeout is how long the connection to a DNS-over-HTTPS resolver is kept open between
+	// two queries
+	dnsOverHTTPSIdleConnectionTimeout = 90 * time.Second
 )
 
 // DNS is the configuration for a Endpoint of type DNS
@@ -27,9 +58,36 @@ type DNS struct {
 
 	// QueryName is the query for DNS
 	QueryName string `yaml:"query-name"`
+
+	// Transport is the protocol used to reach the DNS server, one of udp, tcp, tls and https. Defaults to udp.
+	//
+	// With https, the url of the endpoint is the url of the DNS-over-HTTPS resolver, e.g. https://dns.google/dns-query
+	Transport string `yaml:"transport,omitempty"`
+
+	// ServerName is the name used to validate the certificate of the server when Transport is tls or https.
+	// Defaults to the host of the endpoint url.
+	ServerName string `yaml:"server-name,omitempty"`
+
+	// DNSSEC is whether to request DNSSEC records and validate the chain of trust of the answer
+	DNSSEC bool `yaml:"dnssec,omitempty"`
+
+	// TrustAnchors are the DS or DNSKEY records trusted when validating DNSSEC, in zone file format.
+	// Defaults to the DS record of the root zone's key signing key.
+	TrustAnchors []string `yaml:"trust-anchors,omitempty"`
+
+	// tlsConfig overrides the TLS configuration used when Transport is tls or https
+	tlsConfig *tls.Config
+
+	// httpClient is the client used to reach the DNS-over-HTTPS resolver, so that its connection is reused by every
+	// query instead of opening a new one each time
+	httpClient *http.Client
+
+	trustAnchors []dns.RR
 }
 
-func (d *DNS) validateAndSetDefault() error {
+// validateAndSetDefault validates the configuration of the DNS query sent to the server at the given address and sets
+// the default value of args that have one
+func (d *DNS) validateAndSetDefault(address string) error {
 	if len(d.QueryName) == 0 {
 		return ErrDNSWithNoQueryName
 	}
@@ -39,48 +97,213 @@ func (d *DNS) validateAndSetDefault() error {
 	if _, ok := dns.StringToType[d.QueryType]; !ok {
 		return ErrDNSWithInvalidQueryType
 	}
+	switch d.Transport {
+	case "":
+		d.Transport = DNSTransportUDP
+	case DNSTransportUDP, DNSTransportTCP, DNSTransportTLS, DNSTransportHTTPS:
+	default:
+		return ErrDNSWithInvalidTransport
+	}
+	if d.Transport == DNSTransportHTTPS {
+		urlObject, err := url.Parse(address)
+		if err != nil || urlObject.Scheme != "https" || len(urlObject.Hostname()) == 0 {
+			return ErrDNSOverHTTPSWithInvalidURL
+		}
+		if len(d.ServerName) == 0 {
+			d.ServerName = urlObject.Hostname()
+		}
+		d.httpClient = d.newHTTPClient(urlObject.Host)
+	} else {
+		serverAddress, err := d.serverAddress(address)
+		if err != nil {
+			return err
+		}
+		if d.Transport == DNSTransportTLS && len(d.ServerName) == 0 {
+			d.ServerName, _, _ = net.SplitHostPort(serverAddress)
+		}
+	}
+	if d.DNSSEC {
+		if len(d.TrustAnchors) == 0 {
+			d.TrustAnchors = defaultTrustAnchors
+		}
+		trustAnchors, err := parseTrustAnchors(d.TrustAnchors)
+		if err != nil {
+			return err
+		}
+		d.trustAnchors = trustAnchors
+	}
 	return nil
 }
 
 func (d *DNS) query(url string, result *Result) {
-	if !strings.Contains(url, ":") {
-		url = fmt.Sprintf("%s:%d", url, dnsPort)
-	}
-	queryType := dns.StringToType[d.QueryType]
-	c := new(dns.Client)
 	m := new(dns.Msg)
-	m.SetQuestion(d.QueryName, queryType)
-	r, _, err := c.Exchange(m, url)
+	m.SetQuestion(d.QueryName, dns.StringToType[d.QueryType])
+	if d.DNSSEC {
+		m.SetEdns0(4096, true)
+		// The signatures are validated here, so the server must not withhold records it considers bogus
+		m.CheckingDisabled = true
+	}
+	r, err := d.exchange(m, url)
 	if err != nil {
 		result.AddError(err.Error())
 		return
 	}
 	result.Connected = true
 	result.DNSRCode = dns.RcodeToString[r.Rcode]
+	result.DNSAuthoritative = r.Authoritative
+	answers := make([]string, 0, len(r.Answer))
 	for _, rr := range r.Answer {
-		switch rr.Header().Rrtype {
-		case dns.TypeA:
-			if a, ok := rr.(*dns.A); ok {
-				result.Body = []byte(a.A.String())
-			}
-		case dns.TypeAAAA:
-			if aaaa, ok := rr.(*dns.AAAA); ok {
-				result.Body = []byte(aaaa.AAAA.String())
-			}
-		case dns.TypeCNAME:
-			if cname, ok := rr.(*dns.CNAME); ok {
-				result.Body = []byte(cname.Target)
-			}
-		case dns.TypeMX:
-			if mx, ok := rr.(*dns.MX); ok {
-				result.Body = []byte(mx.Mx)
-			}
-		case dns.TypeNS:
-			if ns, ok := rr.(*dns.NS); ok {
-				result.Body = []byte(ns.Ns)
-			}
-		default:
-			result.Body = []byte("query type is not supported yet")
+		if rr.Header().Rrtype == dns.TypeRRSIG {
+			continue
+		}
+		// The TTL of the answer set is the one that expires first
+		if ttl := rr.Header().Ttl; len(answers) == 0 || ttl < result.DNSTTL {
+			result.DNSTTL = ttl
+		}
+		answers = append(answers, formatDNSAnswer(rr))
+	}
+	result.Body, _ = json.Marshal(answers)
+	if d.DNSSEC {
+		validator := &dnssecValidator{dns: d, address: url, zoneKeys: make(map[string][]*dns.DNSKEY)}
+		result.DNSSEC, err = validator.validate(r)
+		if err != nil {
+			result.AddError(err.Error())
+		}
+		if result.DNSSEC == DNSSECSecure {
+			result.DNSSECExpiration = validator.expiration
+		}
+	}
+}
+
+// exchange sends the message to the server through the configured transport and returns its response
+func (d *DNS) exchange(m *dns.Msg, address string) (*dns.Msg, error) {
+	if d.Transport == DNSTransportHTTPS {
+		return d.exchangeOverHTTPS(m, address)
+	}
+	c := new(dns.Client)
+	switch d.Transport {
+	case DNSTransportTCP:
+		c.Net = "tcp"
+	case DNSTransportTLS:
+		c.Net = "tcp-tls"
+		c.TLSConfig = d.getTLSConfig(address)
+	}
+	address, err := d.serverAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	r, _, err := c.Exchange(m, address)
+	return r, err
+}
+
+// serverAddress returns the host:port of the server at the given address, which defaults to the port of the transport
+func (d *DNS) serverAddress(address string) (string, error) {
+	host, port := address, strconv.Itoa(dnsPort)
+	if d.Transport == DNSTransportTLS {
+		port = strconv.Itoa(dnsOverTLSPort)
+	}
+	// Addresses that can't be split are either a host without port or an IPv6 address with or without brackets
+	if h, p, err := net.SplitHostPort(address); err == nil {
+		host, port = h, p
+	} else if strings.HasPrefix(address, "[") && strings.HasSuffix(address, "]") {
+		host = address[1 : len(address)-1]
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil || len(host) == 0 || strings.ContainsAny(host, "/ ") {
+		return "", fmt.Errorf("%w: %s", ErrDNSWithInvalidAddress, address)
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+// exchangeOverHTTPS sends the message to a DNS-over-HTTPS resolver as described in RFC 8484
+func (d *DNS) exchangeOverHTTPS(m *dns.Msg, resolverURL string) (*dns.Msg, error) {
+	// The ID should be 0 to maximize the chances of the response being cached
+	m.Id = 0
+	packed, err := m.Pack()
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequest(http.MethodPost, resolverURL, bytes.NewReader(packed))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set(ContentTypeHeader, dnsMessageMedia)
+	request.Header.Set("Accept", dnsMessageMedia)
+	request.Header.Set(UserAgentHeader, GatusUserAgent)
+	client := d.httpClient
+	if client == nil {
+		// The configuration wasn't validated, so the connection isn't kept for the next query
+		client = d.newHTTPClient(request.URL.Host)
+		defer client.CloseIdleConnections()
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code from DNS-over-HTTPS resolver: %d", response.StatusCode)
+	}
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	r := new(dns.Msg)
+	if err := r.Unpack(body); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// newHTTPClient returns a client for the DNS-over-HTTPS resolver at the given address
+func (d *DNS) newHTTPClient(address string) *http.Client {
+	return &http.Client{
+		Timeout: util.ConnectionTimeout,
+		Transport: &http.Transport{
+			TLSClientConfig: d.getTLSConfig(address),
+			Proxy:           http.ProxyFromEnvironment,
+			IdleConnTimeout: dnsOverHTTPSIdleConnectionTimeout,
+		},
+	}
+}
+
+// getTLSConfig returns the TLS configuration used to validate the certificate of the server at the given address
+func (d *DNS) getTLSConfig(address string) *tls.Config {
+	tlsConfig := &tls.Config{}
+	if d.tlsConfig != nil {
+		tlsConfig = d.tlsConfig.Clone()
+	}
+	tlsConfig.ServerName = d.ServerName
+	if len(tlsConfig.ServerName) == 0 {
+		if host, _, err := net.SplitHostPort(address); err == nil {
+			tlsConfig.ServerName = host
+		} else {
+			tlsConfig.ServerName = address
 		}
 	}
+	return tlsConfig
+}
+
+// formatDNSAnswer returns the value of a resource record.
+//
+// Records with a single name as value are rendered as that name, and other records are rendered the way they would
+// appear in a zone file, without their header.
+func formatDNSAnswer(rr dns.RR) string {
+	switch record := rr.(type) {
+	case *dns.A:
+		return record.A.String()
+	case *dns.AAAA:
+		return record.AAAA.String()
+	case *dns.CNAME:
+		return record.Target
+	case *dns.MX:
+		return record.Mx
+	case *dns.NS:
+		return record.Ns
+	case *dns.PTR:
+		return record.Ptr
+	case *dns.TXT:
+		return strings.Join(record.Txt, "")
+	default:
+		return strings.TrimPrefix(rr.String(), rr.Header().String())
+	}
 }
@@ -1,10 +1,20 @@
 package core
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
-	"github.com/TwiN/gatus/v5/pattern"
+	"github.com/miekg/dns"
+
+	"github.com/serverless-aliyun/func-status/client/pattern"
 )
 
 func TestIntegrationQuery(t *testing.T) {
@@ -24,7 +34,7 @@ func TestIntegrationQuery(t *testing.T) {
 			},
 			inputURL:        "8.8.8.8",
 			expectedDNSCode: "NOERROR",
-			expectedBody:    "93.184.216.34",
+			expectedBody:    `["93.184.216.34"]`,
 		},
 		{
 			name: "test DNS with type AAAA",
@@ -34,7 +44,7 @@ func TestIntegrationQuery(t *testing.T) {
 			},
 			inputURL:        "8.8.8.8",
 			expectedDNSCode: "NOERROR",
-			expectedBody:    "2606:2800:220:1:248:1893:25c8:1946",
+			expectedBody:    `["2606:2800:220:1:248:1893:25c8:1946"]`,
 		},
 		{
 			name: "test DNS with type CNAME",
@@ -44,7 +54,7 @@ func TestIntegrationQuery(t *testing.T) {
 			},
 			inputURL:        "8.8.8.8",
 			expectedDNSCode: "NOERROR",
-			expectedBody:    "dnsimple.com.",
+			expectedBody:    `["dnsimple.com."]`,
 		},
 		{
 			name: "test DNS with type MX",
@@ -54,7 +64,7 @@ func TestIntegrationQuery(t *testing.T) {
 			},
 			inputURL:        "8.8.8.8",
 			expectedDNSCode: "NOERROR",
-			expectedBody:    ".",
+			expectedBody:    `["."]`,
 		},
 		{
 			name: "test DNS with type NS",
@@ -64,7 +74,7 @@ func TestIntegrationQuery(t *testing.T) {
 			},
 			inputURL:        "8.8.8.8",
 			expectedDNSCode: "NOERROR",
-			expectedBody:    "*.iana-servers.net.",
+			expectedBody:    `*.iana-servers.net.*`,
 		},
 		{
 			name: "test DNS with fake type and retrieve error",
@@ -103,13 +113,370 @@ func TestIntegrationQuery(t *testing.T) {
 	}
 }
 
+// newDNSHandler returns an authoritative handler answering every question with the given records
+func newDNSHandler(t *testing.T, records ...string) dns.Handler {
+	var answer []dns.RR
+	for _, record := range records {
+		rr, err := dns.NewRR(record)
+		if err != nil {
+			t.Fatal(err)
+		}
+		answer = append(answer, rr)
+	}
+	return dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(r)
+		m.Authoritative = true
+		m.Answer = answer
+		_ = w.WriteMsg(m)
+	})
+}
+
+// startDNSServer starts an UDP server answering every question with the given records
+func startDNSServer(t *testing.T, records ...string) string {
+	packetConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	serveDNS(t, &dns.Server{PacketConn: packetConn, Handler: newDNSHandler(t, records...)})
+	return packetConn.LocalAddr().String()
+}
+
+// startStreamDNSServer starts a TCP server answering every question with the given records, over TLS if a
+// configuration is given
+func startStreamDNSServer(t *testing.T, tlsConfig *tls.Config, records ...string) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+	serveDNS(t, &dns.Server{Listener: listener, Handler: newDNSHandler(t, records...)})
+	return address
+}
+
+func serveDNS(t *testing.T, server *dns.Server) {
+	started := make(chan struct{})
+	server.NotifyStartedFunc = func() { close(started) }
+	go server.ActivateAndServe()
+	<-started
+	t.Cleanup(func() { server.Shutdown() })
+}
+
+func TestDNS_query(t *testing.T) {
+	tests := []struct {
+		name                    string
+		queryType               string
+		records                 []string
+		expectedBody            string
+		expectedTTL             uint32
+		expectedNumberOfAnswers string
+		expectedFirstAnswer     string
+	}{
+		{
+			name:                    "A",
+			queryType:               "A",
+			records:                 []string{"example.org. 300 IN A 192.0.2.1", "example.org. 60 IN A 192.0.2.2"},
+			expectedBody:            `["192.0.2.1","192.0.2.2"]`,
+			expectedTTL:             60,
+			expectedNumberOfAnswers: "2",
+			expectedFirstAnswer:     "192.0.2.1",
+		},
+		{
+			name:                    "MX",
+			queryType:               "MX",
+			records:                 []string{"example.org. 300 IN MX 10 mx1.example.org.", "example.org. 300 IN MX 20 mx2.example.org."},
+			expectedBody:            `["mx1.example.org.","mx2.example.org."]`,
+			expectedTTL:             300,
+			expectedNumberOfAnswers: "2",
+			expectedFirstAnswer:     "mx1.example.org.",
+		},
+		{
+			name:                    "TXT",
+			queryType:               "TXT",
+			records:                 []string{`example.org. 3600 IN TXT "v=spf1 " "-all"`},
+			expectedBody:            `["v=spf1 -all"]`,
+			expectedTTL:             3600,
+			expectedNumberOfAnswers: "1",
+			expectedFirstAnswer:     "v=spf1 -all",
+		},
+		{
+			name:                    "SOA",
+			queryType:               "SOA",
+			records:                 []string{"example.org. 3600 IN SOA ns.example.org. hostmaster.example.org. 2024010101 7200 3600 1209600 300"},
+			expectedBody:            `["ns.example.org. hostmaster.example.org. 2024010101 7200 3600 1209600 300"]`,
+			expectedTTL:             3600,
+			expectedNumberOfAnswers: "1",
+			expectedFirstAnswer:     "ns.example.org. hostmaster.example.org. 2024010101 7200 3600 1209600 300",
+		},
+		{
+			name:                    "SRV",
+			queryType:               "SRV",
+			records:                 []string{"_sip._tcp.example.org. 300 IN SRV 10 5 5060 sip.example.org."},
+			expectedBody:            `["10 5 5060 sip.example.org."]`,
+			expectedTTL:             300,
+			expectedNumberOfAnswers: "1",
+			expectedFirstAnswer:     "10 5 5060 sip.example.org.",
+		},
+		{
+			name:                    "PTR",
+			queryType:               "PTR",
+			records:                 []string{"1.2.0.192.in-addr.arpa. 300 IN PTR host.example.org."},
+			expectedBody:            `["host.example.org."]`,
+			expectedTTL:             300,
+			expectedNumberOfAnswers: "1",
+			expectedFirstAnswer:     "host.example.org.",
+		},
+		{
+			name:                    "CAA",
+			queryType:               "CAA",
+			records:                 []string{`example.org. 300 IN CAA 0 issue "letsencrypt.org"`},
+			expectedBody:            `["0 issue \"letsencrypt.org\""]`,
+			expectedTTL:             300,
+			expectedNumberOfAnswers: "1",
+			expectedFirstAnswer:     `0 issue "letsencrypt.org"`,
+		},
+		{
+			name:                    "no-answer",
+			queryType:               "A",
+			expectedBody:            `[]`,
+			expectedNumberOfAnswers: "0",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			address := startDNSServer(t, test.records...)
+			endpoint := Endpoint{
+				Name: test.name,
+				URL:  address,
+				DNS:  &DNS{QueryType: test.queryType, QueryName: "example.org"},
+				Conditions: []Condition{
+					"[DNS_RCODE] == NOERROR",
+					"[DNS_AUTHORITATIVE] == true",
+					Condition("len([BODY]) == " + test.expectedNumberOfAnswers),
+				},
+			}
+			if err := endpoint.ValidateAndSetDefaults(); err != nil {
+				t.Fatal(err)
+			}
+			result := endpoint.EvaluateHealth()
+			if string(result.Body) != test.expectedBody {
+				t.Errorf("expected body %s, got %s", test.expectedBody, result.Body)
+			}
+			if result.DNSTTL != test.expectedTTL {
+				t.Errorf("expected TTL %d, got %d", test.expectedTTL, result.DNSTTL)
+			}
+			if !result.Success {
+				t.Errorf("expected a successful result, got errors %v", result.Errors)
+			}
+			if len(test.records) > 0 {
+				operand, _ := compileOperand("[BODY][0]")
+				if resolved := operand.resolve(result); resolved != test.expectedFirstAnswer {
+					t.Errorf("expected first answer %s, got %s", test.expectedFirstAnswer, resolved)
+				}
+			}
+		})
+	}
+}
+
+func TestDNS_queryWithTransports(t *testing.T) {
+	// The certificate of httptest is valid for example.com
+	dohServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		query := new(dns.Msg)
+		if r.Header.Get("Content-Type") != "application/dns-message" || query.Unpack(body) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		response := new(dns.Msg)
+		response.SetReply(query)
+		rr, _ := dns.NewRR("example.org. 300 IN A 192.0.2.1")
+		response.Answer = []dns.RR{rr}
+		packed, _ := response.Pack()
+		w.Header().Set("Content-Type", "application/dns-message")
+		_, _ = w.Write(packed)
+	}))
+	defer dohServer.Close()
+	pool := x509.NewCertPool()
+	pool.AddCert(dohServer.Certificate())
+	clientTLSConfig := &tls.Config{RootCAs: pool}
+	tcpAddress := startStreamDNSServer(t, nil, "example.org. 300 IN A 192.0.2.1")
+	tlsAddress := startStreamDNSServer(t, dohServer.TLS, "example.org. 300 IN A 192.0.2.1")
+	tests := []struct {
+		name          string
+		url           string
+		transport     string
+		serverName    string
+		isErrExpected bool
+	}{
+		{name: "tcp", url: tcpAddress, transport: DNSTransportTCP},
+		{name: "tls", url: tlsAddress, transport: DNSTransportTLS, serverName: "example.com"},
+		{name: "tls-with-wrong-server-name", url: tlsAddress, transport: DNSTransportTLS, serverName: "dns.example.org", isErrExpected: true},
+		{name: "https", url: dohServer.URL + "/dns-query", transport: DNSTransportHTTPS, serverName: "example.com"},
+		{name: "https-with-wrong-server-name", url: dohServer.URL + "/dns-query", transport: DNSTransportHTTPS, serverName: "dns.example.org", isErrExpected: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			endpoint := Endpoint{
+				Name:       test.name,
+				URL:        test.url,
+				DNS:        &DNS{QueryType: "A", QueryName: "example.org", Transport: test.transport, ServerName: test.serverName, tlsConfig: clientTLSConfig},
+				Conditions: []Condition{"[DNS_RCODE] == NOERROR", "[BODY][0] == 192.0.2.1"},
+			}
+			if err := endpoint.ValidateAndSetDefaults(); err != nil {
+				t.Fatal(err)
+			}
+			result := endpoint.EvaluateHealth()
+			if test.isErrExpected {
+				if result.Success || len(result.Errors) == 0 {
+					t.Error("expected the certificate to be rejected")
+				}
+				return
+			}
+			if !result.Success {
+				t.Errorf("expected a successful result, got errors %v", result.Errors)
+			}
+			if result.Hostname != "127.0.0.1" {
+				t.Errorf("expected hostname 127.0.0.1, got %s", result.Hostname)
+			}
+		})
+	}
+}
+
+func TestDNS_validateAndSetDefaultWithTransport(t *testing.T) {
+	scenarios := []struct {
+		name               string
+		address            string
+		transport          string
+		serverName         string
+		expectedTransport  string
+		expectedServerName string
+		expectedErr        error
+	}{
+		{name: "default-transport", address: "8.8.8.8", expectedTransport: DNSTransportUDP},
+		{name: "udp-with-port", address: "8.8.8.8:5353", transport: DNSTransportUDP, expectedTransport: DNSTransportUDP},
+		{name: "tcp-with-ipv6", address: "2001:4860:4860::8888", transport: DNSTransportTCP, expectedTransport: DNSTransportTCP},
+		{name: "tls-without-port", address: "1.1.1.1", transport: DNSTransportTLS, expectedTransport: DNSTransportTLS, expectedServerName: "1.1.1.1"},
+		{name: "tls-with-bracketed-ipv6", address: "[2606:4700:4700::1111]", transport: DNSTransportTLS, expectedTransport: DNSTransportTLS, expectedServerName: "2606:4700:4700::1111"},
+		{name: "tls-with-server-name", address: "1.1.1.1:853", transport: DNSTransportTLS, serverName: "cloudflare-dns.com", expectedTransport: DNSTransportTLS, expectedServerName: "cloudflare-dns.com"},
+		{name: "tls-with-url", address: "https://dns.google/dns-query", transport: DNSTransportTLS, expectedErr: ErrDNSWithInvalidAddress},
+		{name: "udp-with-invalid-port", address: "8.8.8.8:dns", expectedErr: ErrDNSWithInvalidAddress},
+		{name: "https", address: "https://dns.google/dns-query", transport: DNSTransportHTTPS, expectedTransport: DNSTransportHTTPS, expectedServerName: "dns.google"},
+		{name: "https-without-url", address: "8.8.8.8", transport: DNSTransportHTTPS, expectedErr: ErrDNSOverHTTPSWithInvalidURL},
+		{name: "https-with-http-url", address: "http://dns.google/dns-query", transport: DNSTransportHTTPS, expectedErr: ErrDNSOverHTTPSWithInvalidURL},
+		{name: "invalid-transport", address: "8.8.8.8", transport: "quic", expectedErr: ErrDNSWithInvalidTransport},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			d := &DNS{QueryType: "A", QueryName: "example.org", Transport: scenario.transport, ServerName: scenario.serverName}
+			err := d.validateAndSetDefault(scenario.address)
+			if !errors.Is(err, scenario.expectedErr) {
+				t.Fatalf("expected error %v, got %v", scenario.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if d.Transport != scenario.expectedTransport {
+				t.Errorf("expected transport %s, got %s", scenario.expectedTransport, d.Transport)
+			}
+			if d.ServerName != scenario.expectedServerName {
+				t.Errorf("expected server name %q, got %q", scenario.expectedServerName, d.ServerName)
+			}
+		})
+	}
+}
+
+func TestDNS_serverAddress(t *testing.T) {
+	scenarios := []struct {
+		address         string
+		transport       string
+		expectedAddress string
+	}{
+		{address: "8.8.8.8", transport: DNSTransportUDP, expectedAddress: "8.8.8.8:53"},
+		{address: "8.8.8.8:5353", transport: DNSTransportUDP, expectedAddress: "8.8.8.8:5353"},
+		{address: "::1", transport: DNSTransportTCP, expectedAddress: "[::1]:53"},
+		{address: "[::1]", transport: DNSTransportTCP, expectedAddress: "[::1]:53"},
+		{address: "[::1]", transport: DNSTransportTLS, expectedAddress: "[::1]:853"},
+		{address: "[::1]:5353", transport: DNSTransportUDP, expectedAddress: "[::1]:5353"},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.transport+"/"+scenario.address, func(t *testing.T) {
+			d := &DNS{Transport: scenario.transport}
+			address, err := d.serverAddress(scenario.address)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if address != scenario.expectedAddress {
+				t.Errorf("expected address %s, got %s", scenario.expectedAddress, address)
+			}
+		})
+	}
+}
+
+func TestDNS_queryOverHTTPSReusesConnection(t *testing.T) {
+	dohServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		query := new(dns.Msg)
+		if query.Unpack(body) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		response := new(dns.Msg)
+		response.SetReply(query)
+		packed, _ := response.Pack()
+		w.Header().Set("Content-Type", "application/dns-message")
+		_, _ = w.Write(packed)
+	}))
+	var connections atomic.Int32
+	dohServer.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+	dohServer.StartTLS()
+	defer dohServer.Close()
+	pool := x509.NewCertPool()
+	pool.AddCert(dohServer.Certificate())
+	endpoint := Endpoint{
+		Name:       "doh",
+		URL:        dohServer.URL + "/dns-query",
+		DNS:        &DNS{QueryType: "A", QueryName: "example.org", Transport: DNSTransportHTTPS, ServerName: "example.com", tlsConfig: &tls.Config{RootCAs: pool}},
+		Conditions: []Condition{"[DNS_RCODE] == NOERROR"},
+	}
+	if err := endpoint.ValidateAndSetDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if result := endpoint.EvaluateHealth(); !result.Success {
+			t.Fatalf("expected a successful result, got errors %v", result.Errors)
+		}
+	}
+	if count := connections.Load(); count != 1 {
+		t.Errorf("expected the connection to the resolver to be reused, got %d connections", count)
+	}
+}
+
+func TestEndpoint_ValidateAndSetDefaultsWithDNSOverHTTPSWithoutURL(t *testing.T) {
+	endpoint := Endpoint{
+		Name:       "doh",
+		URL:        "8.8.8.8",
+		DNS:        &DNS{QueryType: "A", QueryName: "example.org", Transport: DNSTransportHTTPS},
+		Conditions: []Condition{"[DNS_RCODE] == NOERROR"},
+	}
+	if err := endpoint.ValidateAndSetDefaults(); err != ErrDNSOverHTTPSWithInvalidURL {
+		t.Errorf("expected error %v, got %v", ErrDNSOverHTTPSWithInvalidURL, err)
+	}
+}
+
 func TestEndpoint_ValidateAndSetDefaultsWithNoDNSQueryName(t *testing.T) {
 	defer func() { recover() }()
 	dns := &DNS{
 		QueryType: "A",
 		QueryName: "",
 	}
-	err := dns.validateAndSetDefault()
+	err := dns.validateAndSetDefault("8.8.8.8")
 	if err == nil {
 		t.Fatal("Should've returned an error because endpoint's dns didn't have a query name, which is a mandatory field for dns")
 	}
@@ -121,7 +488,7 @@ func TestEndpoint_ValidateAndSetDefaultsWithInvalidDNSQueryType(t *testing.T) {
 		QueryType: "B",
 		QueryName: "example.com",
 	}
-	err := dns.validateAndSetDefault()
+	err := dns.validateAndSetDefault("8.8.8.8")
 	if err == nil {
 		t.Fatal("Should've returned an error because endpoint's dns query type is invalid, it needs to be a valid query name like A, AAAA, CNAME...")
 	}
@@ -0,0 +1,438 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+const (
+	// DNSSECSecure is the DNSSEC status of an answer whose signatures could be validated up to a trust anchor
+	DNSSECSecure = "secure"
+
+	// DNSSECInsecure is the DNSSEC status of an answer that belongs to a zone whose delegation is proven not to be signed
+	DNSSECInsecure = "insecure"
+
+	// DNSSECBogus is the DNSSEC status of an answer whose signatures are missing, invalid or expired
+	DNSSECBogus = "bogus"
+
+	// maximumDNSSECChainLength is the maximum number of zones walked to reach a trust anchor
+	maximumDNSSECChainLength = 16
+)
+
+var (
+	// ErrDNSWithInvalidTrustAnchor is the error with which gatus will panic if a dns is configured with a trust anchor
+	// that is neither a DS nor a DNSKEY record
+	ErrDNSWithInvalidTrustAnchor = errors.New("invalid trust anchor: must be a DS or DNSKEY record")
+
+	// errDNSSECInsecureDelegation is returned when the parent of a zone proves that the zone has no DS record
+	errDNSSECInsecureDelegation = errors.New("insecure delegation")
+
+	// defaultTrustAnchors are used when none is configured. This is the DS record of the root zone's KSK-2017.
+	defaultTrustAnchors = []string{". 172800 IN DS 20326 8 2 E06D44B80B8F1D39A95C0B0D7C65D08458E880409BBC683457104237C7F8EC8D"}
+)
+
+func parseTrustAnchors(anchors []string) ([]dns.RR, error) {
+	var trustAnchors []dns.RR
+	for _, anchor := range anchors {
+		rr, err := dns.NewRR(anchor)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrDNSWithInvalidTrustAnchor, err)
+		}
+		switch rr.(type) {
+		case *dns.DS, *dns.DNSKEY:
+			trustAnchors = append(trustAnchors, rr)
+		default:
+			return nil, ErrDNSWithInvalidTrustAnchor
+		}
+	}
+	return trustAnchors, nil
+}
+
+// dnssecValidator validates the chain of trust of answers by querying the same server for DNSKEY and DS records
+type dnssecValidator struct {
+	dns     *DNS
+	address string
+
+	// zoneKeys are the keys of the zones that have already been authenticated
+	zoneKeys map[string][]*dns.DNSKEY
+
+	// expiration is the duration before the first of the validated signatures expires
+	expiration time.Duration
+}
+
+// dnssecDenial is what an authenticated NSEC or NSEC3 record proves about a name
+type dnssecDenial struct {
+	// exists is whether the name exists, in which case types are the types of the records it has
+	exists bool
+	types  []uint16
+
+	// optOut is whether the name may be an unsigned delegation covered by an NSEC3 record with the opt-out flag
+	optOut bool
+}
+
+// validate returns the DNSSEC status of the response, as well as the reason it is not secure.
+//
+// Records may be stripped by anyone on the path to the server, so an answer is only insecure if the absence of a DS
+// record for one of the zones above it is proven by an authenticated NSEC or NSEC3 record, and bogus otherwise.
+func (v *dnssecValidator) validate(r *dns.Msg) (string, error) {
+	rrsets, signatures := splitRRsets(r.Answer)
+	if len(rrsets) == 0 {
+		if len(r.Question) == 0 {
+			return DNSSECBogus, errors.New("no question in the response")
+		}
+		question := r.Question[0]
+		_, err := v.denialOf(question.Name, question.Qtype, r, 0)
+		if err == nil {
+			return DNSSECSecure, nil
+		}
+		if insecureErr := v.proveInsecure(question.Name, 0); errors.Is(insecureErr, errDNSSECInsecureDelegation) {
+			return DNSSECInsecure, nil
+		}
+		return DNSSECBogus, err
+	}
+	status := DNSSECSecure
+	for key, rrset := range rrsets {
+		if len(signatures[key]) == 0 {
+			header := rrset[0].Header()
+			if err := v.proveInsecure(header.Name, 0); !errors.Is(err, errDNSSECInsecureDelegation) {
+				return DNSSECBogus, fmt.Errorf("no RRSIG found for %s %s: %v", header.Name, dns.TypeToString[header.Rrtype], err)
+			}
+			status = DNSSECInsecure
+			continue
+		}
+		keys, err := v.keysOf(signatures[key][0].SignerName, 0)
+		if errors.Is(err, errDNSSECInsecureDelegation) {
+			status = DNSSECInsecure
+			continue
+		}
+		if err != nil {
+			return DNSSECBogus, err
+		}
+		if err := v.verify(rrset, signatures[key], keys); err != nil {
+			return DNSSECBogus, err
+		}
+	}
+	return status, nil
+}
+
+// keysOf returns the authenticated keys of a zone
+func (v *dnssecValidator) keysOf(zone string, depth int) ([]*dns.DNSKEY, error) {
+	if keys, ok := v.zoneKeys[zone]; ok {
+		return keys, nil
+	}
+	if depth >= maximumDNSSECChainLength {
+		return nil, fmt.Errorf("no trust anchor found within %d zones", maximumDNSSECChainLength)
+	}
+	keySet, keySignatures, err := v.query(zone, dns.TypeDNSKEY)
+	if err != nil {
+		return nil, err
+	}
+	var keys []*dns.DNSKEY
+	for _, rr := range keySet {
+		if key, ok := rr.(*dns.DNSKEY); ok {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no DNSKEY found for %s", zone)
+	}
+	anchors := v.anchorsOf(zone)
+	if len(anchors) == 0 {
+		if zone == "." {
+			return nil, errors.New("no trust anchor configured for the root zone")
+		}
+		r, err := v.exchange(zone, dns.TypeDS)
+		if err != nil {
+			return nil, err
+		}
+		dsSet, dsSignatures := rrsetOf(r, zone, dns.TypeDS)
+		if len(dsSet) == 0 {
+			if err := v.denyDS(zone, r, depth+1); err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%s is signed, but is not delegated by its parent zone", zone)
+		}
+		// The DS record of a zone is signed by its parent zone, so a zone can't vouch for its own keys
+		dsSignatures = signaturesByParentOf(zone, dsSignatures)
+		if len(dsSignatures) == 0 {
+			return nil, fmt.Errorf("no RRSIG of a parent zone found for the DS of %s", zone)
+		}
+		parentKeys, err := v.keysOf(dsSignatures[0].SignerName, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		if err := v.verify(dsSet, dsSignatures, parentKeys); err != nil {
+			return nil, err
+		}
+		anchors = dsSet
+	}
+	trustedKeys := keysMatchingAnchors(keys, anchors)
+	if len(trustedKeys) == 0 {
+		return nil, fmt.Errorf("no DNSKEY of %s matches its trust anchors", zone)
+	}
+	// The key set must be signed by one of the keys trusted either directly or through the parent zone
+	if err := v.verify(keySet, keySignatures, trustedKeys); err != nil {
+		return nil, err
+	}
+	v.zoneKeys[zone] = keys
+	return keys, nil
+}
+
+// proveInsecure returns errDNSSECInsecureDelegation if the name belongs to a zone whose delegation is proven not to
+// be signed, and the reason it isn't proven otherwise.
+//
+// The zones between the closest trust anchor and the name are walked down, so that the DS record of each of them is
+// asked to the parent zone that would have signed it.
+func (v *dnssecValidator) proveInsecure(name string, depth int) error {
+	labels := dns.SplitDomainName(name)
+	anchored := -1
+	for i := 0; i <= len(labels) && anchored < 0; i++ {
+		if len(v.anchorsOf(dns.Fqdn(strings.Join(labels[i:], ".")))) > 0 {
+			anchored = i
+		}
+	}
+	if anchored < 0 {
+		return fmt.Errorf("no trust anchor configured for %s", name)
+	}
+	for i := anchored - 1; i >= 0; i-- {
+		zone := dns.Fqdn(strings.Join(labels[i:], "."))
+		r, err := v.exchange(zone, dns.TypeDS)
+		if err != nil {
+			return err
+		}
+		if dsSet, _ := rrsetOf(r, zone, dns.TypeDS); len(dsSet) > 0 {
+			// The zone is signed, so the delegations below it must be proven to be unsigned as well
+			continue
+		}
+		if err := v.denyDS(zone, r, depth+1); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("%s belongs to a signed zone", name)
+}
+
+// denyDS checks the proof that the response to the DS query of a zone has no DS record.
+//
+// It returns errDNSSECInsecureDelegation if the zone is an unsigned delegation, nil if the name is not a delegation
+// at all, and the reason the absence of DS record isn't proven otherwise.
+func (v *dnssecValidator) denyDS(zone string, r *dns.Msg, depth int) error {
+	denial, err := v.denialOf(zone, dns.TypeDS, r, depth)
+	if err != nil {
+		return err
+	}
+	if denial.optOut {
+		return errDNSSECInsecureDelegation
+	}
+	if !denial.exists {
+		return nil
+	}
+	if hasType(denial.types, dns.TypeSOA) {
+		// The NSEC record at the apex of a zone is signed by the zone itself, which can't deny its own DS record
+		return fmt.Errorf("the DS record of %s is denied by the zone itself", zone)
+	}
+	if hasType(denial.types, dns.TypeNS) {
+		return errDNSSECInsecureDelegation
+	}
+	return nil
+}
+
+// denialOf returns what the authenticated NSEC or NSEC3 records of the authority section of the response prove about
+// the name, provided that they prove that the name has no record of the given type
+func (v *dnssecValidator) denialOf(name string, queryType uint16, r *dns.Msg, depth int) (*dnssecDenial, error) {
+	rrsets, signatures := splitRRsets(r.Ns)
+	for key, rrset := range rrsets {
+		var denial *dnssecDenial
+		switch record := rrset[0].(type) {
+		case *dns.NSEC:
+			if strings.EqualFold(record.Hdr.Name, name) {
+				denial = &dnssecDenial{exists: true, types: record.TypeBitMap}
+			} else if nsecCovers(record, name) {
+				denial = &dnssecDenial{}
+			}
+		case *dns.NSEC3:
+			if record.Match(name) {
+				denial = &dnssecDenial{exists: true, types: record.TypeBitMap}
+			} else if record.Cover(name) {
+				denial = &dnssecDenial{optOut: record.Flags&1 == 1}
+			}
+		}
+		if denial == nil || len(signatures[key]) == 0 || !dns.IsSubDomain(signatures[key][0].SignerName, name) {
+			continue
+		}
+		if denial.exists && (hasType(denial.types, queryType) || hasType(denial.types, dns.TypeCNAME)) {
+			return nil, fmt.Errorf("%s has a %s record according to its %s record", name, dns.TypeToString[queryType], dns.TypeToString[rrset[0].Header().Rrtype])
+		}
+		keys, err := v.keysOf(signatures[key][0].SignerName, depth)
+		if err != nil {
+			return nil, err
+		}
+		if err := v.verify(rrset, signatures[key], keys); err != nil {
+			return nil, err
+		}
+		return denial, nil
+	}
+	return nil, fmt.Errorf("no signed NSEC or NSEC3 record proves that %s has no %s record", name, dns.TypeToString[queryType])
+}
+
+// anchorsOf returns the configured trust anchors of a zone
+func (v *dnssecValidator) anchorsOf(zone string) []dns.RR {
+	var anchors []dns.RR
+	for _, anchor := range v.dns.trustAnchors {
+		if strings.EqualFold(anchor.Header().Name, zone) {
+			anchors = append(anchors, anchor)
+		}
+	}
+	return anchors
+}
+
+// verify checks that one of the signatures of the rrset was made by one of the keys and is currently valid
+func (v *dnssecValidator) verify(rrset []dns.RR, signatures []*dns.RRSIG, keys []*dns.DNSKEY) error {
+	now := time.Now()
+	for _, signature := range signatures {
+		if !signature.ValidityPeriod(now) {
+			continue
+		}
+		for _, key := range keys {
+			if key.KeyTag() != signature.KeyTag || key.Algorithm != signature.Algorithm {
+				continue
+			}
+			if err := signature.Verify(key, rrset); err == nil {
+				expiration := time.Until(time.Unix(int64(signature.Expiration), 0))
+				if v.expiration == 0 || expiration < v.expiration {
+					v.expiration = expiration
+				}
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("no valid RRSIG found for %s %s", rrset[0].Header().Name, dns.TypeToString[rrset[0].Header().Rrtype])
+}
+
+// query returns the records of the given type, along with their signatures
+func (v *dnssecValidator) query(name string, queryType uint16) ([]dns.RR, []*dns.RRSIG, error) {
+	r, err := v.exchange(name, queryType)
+	if err != nil {
+		return nil, nil, err
+	}
+	rrset, signatures := rrsetOf(r, name, queryType)
+	return rrset, signatures, nil
+}
+
+// exchange sends a query with DNSSEC records requested to the server
+func (v *dnssecValidator) exchange(name string, queryType uint16) (*dns.Msg, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(name, queryType)
+	m.SetEdns0(4096, true)
+	// The signatures are validated here, so the server must not withhold records it considers bogus
+	m.CheckingDisabled = true
+	return v.dns.exchange(m, v.address)
+}
+
+// rrsetOf returns the records of the given name and type from the answer of the response, along with their signatures
+//
+// Signatures of another name, or made by a zone the name doesn't belong to, are ignored.
+func rrsetOf(r *dns.Msg, name string, queryType uint16) ([]dns.RR, []*dns.RRSIG) {
+	var rrset []dns.RR
+	var signatures []*dns.RRSIG
+	for _, rr := range r.Answer {
+		if signature, ok := rr.(*dns.RRSIG); ok {
+			if signature.TypeCovered == queryType && strings.EqualFold(rr.Header().Name, name) && dns.IsSubDomain(signature.SignerName, name) {
+				signatures = append(signatures, signature)
+			}
+		} else if rr.Header().Rrtype == queryType && strings.EqualFold(rr.Header().Name, name) {
+			rrset = append(rrset, rr)
+		}
+	}
+	return rrset, signatures
+}
+
+// splitRRsets groups the records of an answer by name and type, and the signatures by the rrset they cover.
+//
+// Signatures made by a zone their owner doesn't belong to are ignored, as any signed zone could otherwise vouch for the
+// records of another zone.
+func splitRRsets(answer []dns.RR) (map[string][]dns.RR, map[string][]*dns.RRSIG) {
+	rrsets := make(map[string][]dns.RR)
+	signatures := make(map[string][]*dns.RRSIG)
+	for _, rr := range answer {
+		if signature, ok := rr.(*dns.RRSIG); ok {
+			if !dns.IsSubDomain(signature.SignerName, rr.Header().Name) {
+				continue
+			}
+			key := strings.ToLower(rr.Header().Name) + "/" + dns.TypeToString[signature.TypeCovered]
+			signatures[key] = append(signatures[key], signature)
+			continue
+		}
+		key := strings.ToLower(rr.Header().Name) + "/" + dns.TypeToString[rr.Header().Rrtype]
+		rrsets[key] = append(rrsets[key], rr)
+	}
+	return rrsets, signatures
+}
+
+// signaturesByParentOf returns the signatures made by one of the zones above the given zone
+func signaturesByParentOf(zone string, signatures []*dns.RRSIG) []*dns.RRSIG {
+	var parentSignatures []*dns.RRSIG
+	for _, signature := range signatures {
+		if dns.IsSubDomain(signature.SignerName, zone) && !strings.EqualFold(dns.Fqdn(signature.SignerName), dns.Fqdn(zone)) {
+			parentSignatures = append(parentSignatures, signature)
+		}
+	}
+	return parentSignatures
+}
+
+// keysMatchingAnchors returns the keys matching one of the DS or DNSKEY anchors
+func keysMatchingAnchors(keys []*dns.DNSKEY, anchors []dns.RR) []*dns.DNSKEY {
+	var matchingKeys []*dns.DNSKEY
+	for _, key := range keys {
+		for _, anchor := range anchors {
+			matches := false
+			switch anchor := anchor.(type) {
+			case *dns.DS:
+				ds := key.ToDS(anchor.DigestType)
+				matches = ds != nil && ds.KeyTag == anchor.KeyTag && strings.EqualFold(ds.Digest, anchor.Digest)
+			case *dns.DNSKEY:
+				matches = key.Algorithm == anchor.Algorithm && key.PublicKey == anchor.PublicKey
+			}
+			if matches {
+				matchingKeys = append(matchingKeys, key)
+				break
+			}
+		}
+	}
+	return matchingKeys
+}
+
+// nsecCovers returns whether the name falls strictly between the owner and the next name of the NSEC record, in which
+// case the name doesn't exist
+func nsecCovers(nsec *dns.NSEC, name string) bool {
+	afterOwner := compareCanonically(nsec.Hdr.Name, name) < 0
+	beforeNext := compareCanonically(name, nsec.NextDomain) < 0
+	if compareCanonically(nsec.Hdr.Name, nsec.NextDomain) < 0 {
+		return afterOwner && beforeNext
+	}
+	// The last NSEC record of a zone points back to its apex
+	return afterOwner || beforeNext
+}
+
+// compareCanonically compares two names in the canonical order of RFC 4034, which compares their labels from right to left
+func compareCanonically(a, b string) int {
+	aLabels, bLabels := dns.SplitDomainName(strings.ToLower(a)), dns.SplitDomainName(strings.ToLower(b))
+	for i, j := len(aLabels)-1, len(bLabels)-1; i >= 0 && j >= 0; i, j = i-1, j-1 {
+		if c := strings.Compare(aLabels[i], bLabels[j]); c != 0 {
+			return c
+		}
+	}
+	return len(aLabels) - len(bLabels)
+}
+
+func hasType(types []uint16, t uint16) bool {
+	for _, candidate := range types {
+		if candidate == t {
+			return true
+		}
+	}
+	return false
+}
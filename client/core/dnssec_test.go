@@ -0,0 +1,295 @@
+package core
+
+import (
+	"crypto"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+type signingKey struct {
+	dnskey  *dns.DNSKEY
+	private crypto.Signer
+}
+
+func newSigningKey(t *testing.T, zone string) *signingKey {
+	dnskey := &dns.DNSKEY{
+		Hdr:       dns.RR_Header{Name: zone, Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET, Ttl: 3600},
+		Flags:     257,
+		Protocol:  3,
+		Algorithm: dns.ECDSAP256SHA256,
+	}
+	private, err := dnskey.Generate(256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &signingKey{dnskey: dnskey, private: private.(crypto.Signer)}
+}
+
+func (k *signingKey) sign(t *testing.T, rrset []dns.RR, expiration time.Time) *dns.RRSIG {
+	header := rrset[0].Header()
+	signature := &dns.RRSIG{
+		Hdr:         dns.RR_Header{Name: header.Name, Rrtype: dns.TypeRRSIG, Class: dns.ClassINET, Ttl: header.Ttl},
+		TypeCovered: header.Rrtype,
+		Algorithm:   k.dnskey.Algorithm,
+		Labels:      uint8(dns.CountLabel(header.Name)),
+		OrigTtl:     header.Ttl,
+		Expiration:  uint32(expiration.Unix()),
+		Inception:   uint32(expiration.Add(-30 * 24 * time.Hour).Unix()),
+		KeyTag:      k.dnskey.KeyTag(),
+		SignerName:  k.dnskey.Hdr.Name,
+	}
+	if err := signature.Sign(k.private, rrset); err != nil {
+		t.Fatal(err)
+	}
+	return signature
+}
+
+// startSignedDNSServer starts an UDP server answering questions from the given records, keyed by name and type
+func startSignedDNSServer(t *testing.T, records map[string][]dns.RR) string {
+	packetConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	serveDNS(t, &dns.Server{PacketConn: packetConn, Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(r)
+		question := r.Question[0]
+		m.Answer = records[strings.ToLower(question.Name)+"/"+dns.TypeToString[question.Qtype]]
+		if len(m.Answer) == 0 {
+			// The records proving that the name has no record of the type asked, e.g. NSEC
+			m.Ns = records[strings.ToLower(question.Name)+"/denial"]
+		}
+		_ = w.WriteMsg(m)
+	})})
+	return packetConn.LocalAddr().String()
+}
+
+func TestDNS_queryWithDNSSEC(t *testing.T) {
+	orgKey, exampleKey, otherKey := newSigningKey(t, "org."), newSigningKey(t, "example.org."), newSigningKey(t, "org.")
+	// attacker.org. is properly delegated by org., but has no authority over example.org.
+	attackerKey := newSigningKey(t, "attacker.org.")
+	attackerDS := attackerKey.dnskey.ToDS(dns.SHA256)
+	attackerDS.Hdr.Ttl = 3600
+	forgedA, _ := dns.NewRR("example.org. 300 IN A 6.6.6.6")
+	inAMonth, inTwoHours, anHourAgo := time.Now().Add(30*24*time.Hour), time.Now().Add(2*time.Hour), time.Now().Add(-time.Hour)
+	a, _ := dns.NewRR("example.org. 300 IN A 192.0.2.1")
+	exampleDS := exampleKey.dnskey.ToDS(dns.SHA256)
+	exampleDS.Hdr.Ttl = 3600
+	// The NSEC record of the parent zone at an unsigned delegation lists NS, but not DS
+	delegationNSEC, _ := dns.NewRR("example.org. 3600 IN NSEC www.org. NS RRSIG NSEC")
+	apexNSEC, _ := dns.NewRR("example.org. 3600 IN NSEC www.example.org. NS SOA RRSIG NSEC DNSKEY")
+	optOutNSEC3, _ := dns.NewRR("00000000000000000000000000000000.org. 3600 IN NSEC3 1 1 0 - VVVVVVVVVVVVVVVVVVVVVVVVVVVVVVVV NS")
+	scenarios := []struct {
+		name               string
+		answer             []dns.RR
+		delegated          bool
+		dsSigner           *signingKey
+		denial             []dns.RR
+		trustAnchor        string
+		expectedStatus     string
+		expectedExpiration time.Duration
+	}{
+		{
+			name:               "secure",
+			answer:             []dns.RR{a, exampleKey.sign(t, []dns.RR{a}, inTwoHours)},
+			delegated:          true,
+			trustAnchor:        orgKey.dnskey.ToDS(dns.SHA256).String(),
+			expectedStatus:     DNSSECSecure,
+			expectedExpiration: 2 * time.Hour,
+		},
+		{
+			name:               "secure-with-dnskey-anchor",
+			answer:             []dns.RR{a, exampleKey.sign(t, []dns.RR{a}, inTwoHours)},
+			delegated:          true,
+			trustAnchor:        orgKey.dnskey.String(),
+			expectedStatus:     DNSSECSecure,
+			expectedExpiration: 2 * time.Hour,
+		},
+		{
+			name:               "secure-denial-of-existence",
+			delegated:          true,
+			denial:             []dns.RR{apexNSEC, exampleKey.sign(t, []dns.RR{apexNSEC}, inTwoHours)},
+			trustAnchor:        orgKey.dnskey.ToDS(dns.SHA256).String(),
+			expectedStatus:     DNSSECSecure,
+			expectedExpiration: 2 * time.Hour,
+		},
+		{
+			name:           "expired-signature",
+			answer:         []dns.RR{a, exampleKey.sign(t, []dns.RR{a}, anHourAgo)},
+			delegated:      true,
+			trustAnchor:    orgKey.dnskey.ToDS(dns.SHA256).String(),
+			expectedStatus: DNSSECBogus,
+		},
+		{
+			name:           "untrusted-anchor",
+			answer:         []dns.RR{a, exampleKey.sign(t, []dns.RR{a}, inTwoHours)},
+			delegated:      true,
+			trustAnchor:    otherKey.dnskey.ToDS(dns.SHA256).String(),
+			expectedStatus: DNSSECBogus,
+		},
+		{
+			name:           "signed-by-another-zone",
+			answer:         []dns.RR{forgedA, attackerKey.sign(t, []dns.RR{forgedA}, inTwoHours)},
+			delegated:      true,
+			trustAnchor:    orgKey.dnskey.ToDS(dns.SHA256).String(),
+			expectedStatus: DNSSECBogus,
+		},
+		{
+			name:           "ds-signed-by-the-zone-itself",
+			answer:         []dns.RR{a, exampleKey.sign(t, []dns.RR{a}, inTwoHours)},
+			delegated:      true,
+			dsSigner:       exampleKey,
+			trustAnchor:    orgKey.dnskey.ToDS(dns.SHA256).String(),
+			expectedStatus: DNSSECBogus,
+		},
+		{
+			name:           "stripped-signatures",
+			answer:         []dns.RR{a},
+			delegated:      true,
+			trustAnchor:    orgKey.dnskey.ToDS(dns.SHA256).String(),
+			expectedStatus: DNSSECBogus,
+		},
+		{
+			name:           "stripped-ds",
+			answer:         []dns.RR{a, exampleKey.sign(t, []dns.RR{a}, inTwoHours)},
+			delegated:      false,
+			trustAnchor:    orgKey.dnskey.ToDS(dns.SHA256).String(),
+			expectedStatus: DNSSECBogus,
+		},
+		{
+			name:           "stripped-signatures-and-ds",
+			answer:         []dns.RR{a},
+			delegated:      false,
+			trustAnchor:    orgKey.dnskey.ToDS(dns.SHA256).String(),
+			expectedStatus: DNSSECBogus,
+		},
+		{
+			name:           "unsigned-denial-of-ds",
+			answer:         []dns.RR{a},
+			delegated:      false,
+			denial:         []dns.RR{delegationNSEC},
+			trustAnchor:    orgKey.dnskey.ToDS(dns.SHA256).String(),
+			expectedStatus: DNSSECBogus,
+		},
+		{
+			name:           "denial-of-ds-by-the-zone-itself",
+			answer:         []dns.RR{a, exampleKey.sign(t, []dns.RR{a}, inTwoHours)},
+			delegated:      false,
+			denial:         []dns.RR{apexNSEC, exampleKey.sign(t, []dns.RR{apexNSEC}, inTwoHours)},
+			trustAnchor:    orgKey.dnskey.ToDS(dns.SHA256).String(),
+			expectedStatus: DNSSECBogus,
+		},
+		{
+			name:           "insecure-delegation",
+			answer:         []dns.RR{a},
+			delegated:      false,
+			denial:         []dns.RR{delegationNSEC, orgKey.sign(t, []dns.RR{delegationNSEC}, inAMonth)},
+			trustAnchor:    orgKey.dnskey.ToDS(dns.SHA256).String(),
+			expectedStatus: DNSSECInsecure,
+		},
+		{
+			name:           "insecure-delegation-with-signed-answer",
+			answer:         []dns.RR{a, exampleKey.sign(t, []dns.RR{a}, inTwoHours)},
+			delegated:      false,
+			denial:         []dns.RR{delegationNSEC, orgKey.sign(t, []dns.RR{delegationNSEC}, inAMonth)},
+			trustAnchor:    orgKey.dnskey.ToDS(dns.SHA256).String(),
+			expectedStatus: DNSSECInsecure,
+		},
+		{
+			name:           "insecure-delegation-with-nsec3-opt-out",
+			answer:         []dns.RR{a},
+			delegated:      false,
+			denial:         []dns.RR{optOutNSEC3, orgKey.sign(t, []dns.RR{optOutNSEC3}, inAMonth)},
+			trustAnchor:    orgKey.dnskey.ToDS(dns.SHA256).String(),
+			expectedStatus: DNSSECInsecure,
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			records := map[string][]dns.RR{
+				"example.org./A":       scenario.answer,
+				"example.org./DNSKEY":  {exampleKey.dnskey, exampleKey.sign(t, []dns.RR{exampleKey.dnskey}, inAMonth)},
+				"example.org./denial":  scenario.denial,
+				"org./DNSKEY":          {orgKey.dnskey, orgKey.sign(t, []dns.RR{orgKey.dnskey}, inAMonth)},
+				"attacker.org./DNSKEY": {attackerKey.dnskey, attackerKey.sign(t, []dns.RR{attackerKey.dnskey}, inAMonth)},
+				"attacker.org./DS":     {attackerDS, orgKey.sign(t, []dns.RR{attackerDS}, inAMonth)},
+			}
+			if scenario.delegated {
+				dsSigner := orgKey
+				if scenario.dsSigner != nil {
+					dsSigner = scenario.dsSigner
+				}
+				records["example.org./DS"] = []dns.RR{exampleDS, dsSigner.sign(t, []dns.RR{exampleDS}, inAMonth)}
+			}
+			endpoint := Endpoint{
+				Name:       scenario.name,
+				URL:        startSignedDNSServer(t, records),
+				DNS:        &DNS{QueryType: "A", QueryName: "example.org", DNSSEC: true, TrustAnchors: []string{scenario.trustAnchor}},
+				Conditions: []Condition{"[DNSSEC] == secure", "[DNSSEC_EXPIRATION] > 1h"},
+			}
+			if err := endpoint.ValidateAndSetDefaults(); err != nil {
+				t.Fatal(err)
+			}
+			result := endpoint.EvaluateHealth()
+			if result.DNSSEC != scenario.expectedStatus {
+				t.Errorf("expected DNSSEC status %s, got %s with errors %v", scenario.expectedStatus, result.DNSSEC, result.Errors)
+			}
+			if difference := result.DNSSECExpiration - scenario.expectedExpiration; difference > time.Minute || difference < -time.Minute {
+				t.Errorf("expected DNSSEC expiration to be about %s, got %s", scenario.expectedExpiration, result.DNSSECExpiration)
+			}
+			if expectedSuccess := scenario.expectedStatus == DNSSECSecure; result.Success != expectedSuccess {
+				t.Errorf("expected success to be %v, got %v", expectedSuccess, result.Success)
+			}
+			if len(scenario.answer) > 0 {
+				if expectedBody := `["` + scenario.answer[0].(*dns.A).A.String() + `"]`; string(result.Body) != expectedBody {
+					t.Errorf("expected the body to be %s, got %s", expectedBody, result.Body)
+				}
+			}
+		})
+	}
+}
+
+func TestNSECCovers(t *testing.T) {
+	scenarios := []struct {
+		nsec          string
+		name          string
+		expectedCover bool
+	}{
+		{nsec: "a.org. 3600 IN NSEC d.org. NS", name: "b.org.", expectedCover: true},
+		{nsec: "a.org. 3600 IN NSEC d.org. NS", name: "x.b.org.", expectedCover: true},
+		{nsec: "a.org. 3600 IN NSEC d.org. NS", name: "A.org.", expectedCover: false},
+		{nsec: "a.org. 3600 IN NSEC d.org. NS", name: "d.org.", expectedCover: false},
+		{nsec: "a.org. 3600 IN NSEC d.org. NS", name: "e.org.", expectedCover: false},
+		{nsec: "x.org. 3600 IN NSEC org. NS", name: "z.org.", expectedCover: true},
+		{nsec: "x.org. 3600 IN NSEC org. NS", name: "b.org.", expectedCover: false},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.nsec+"/"+scenario.name, func(t *testing.T) {
+			nsec, err := dns.NewRR(scenario.nsec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if covers := nsecCovers(nsec.(*dns.NSEC), scenario.name); covers != scenario.expectedCover {
+				t.Errorf("expected cover to be %v, got %v", scenario.expectedCover, covers)
+			}
+		})
+	}
+}
+
+func TestDNS_validateAndSetDefaultWithDNSSEC(t *testing.T) {
+	d := &DNS{QueryType: "A", QueryName: "example.org", DNSSEC: true}
+	if err := d.validateAndSetDefault("8.8.8.8"); err != nil {
+		t.Fatal(err)
+	}
+	if len(d.trustAnchors) != 1 || d.trustAnchors[0].Header().Name != "." {
+		t.Errorf("expected the root trust anchor to be used by default, got %v", d.trustAnchors)
+	}
+	d.TrustAnchors = []string{"example.org. 300 IN A 192.0.2.1"}
+	if err := d.validateAndSetDefault("8.8.8.8"); err != ErrDNSWithInvalidTrustAnchor {
+		t.Errorf("expected error %v, got %v", ErrDNSWithInvalidTrustAnchor, err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/Masterminds/semver/v3"
+	"github.com/serverless-aliyun/func-status/client/alerting/alert"
 	"github.com/serverless-aliyun/func-status/client/util"
 	"io"
 	"net"
@@ -31,10 +32,23 @@ const (
 	// GatusUserAgent is the default user agent that Gatus uses to send requests.
 	GatusUserAgent = "Gatus/1.0"
 
-	EndpointTypeDNS     EndpointType = "DNS"
-	EndpointTypeHTTP    EndpointType = "HTTP"
-	EndpointTypeVERSION EndpointType = "VERSION"
-	EndpointTypeUNKNOWN EndpointType = "UNKNOWN"
+	EndpointTypeDNS      EndpointType = "DNS"
+	EndpointTypeHTTP     EndpointType = "HTTP"
+	EndpointTypeVERSION  EndpointType = "VERSION"
+	EndpointTypeTCP      EndpointType = "TCP"
+	EndpointTypeUDP      EndpointType = "UDP"
+	EndpointTypeTLS      EndpointType = "TLS"
+	EndpointTypeICMP     EndpointType = "ICMP"
+	EndpointTypeGRPC     EndpointType = "GRPC"
+	EndpointTypeWS       EndpointType = "WEBSOCKET"
+	EndpointTypeSSH      EndpointType = "SSH"
+	EndpointTypeSMTP     EndpointType = "SMTP"
+	EndpointTypeIMAP     EndpointType = "IMAP"
+	EndpointTypePOP3     EndpointType = "POP3"
+	EndpointTypePOSTGRES EndpointType = "POSTGRES"
+	EndpointTypeMYSQL    EndpointType = "MYSQL"
+	EndpointTypeREDIS    EndpointType = "REDIS"
+	EndpointTypeUNKNOWN  EndpointType = "UNKNOWN"
 )
 
 var (
@@ -53,9 +67,15 @@ var (
 	// ErrUnknownEndpointType is the error with which Gatus will panic if an endpoint has an unknown type
 	ErrUnknownEndpointType = errors.New("unknown endpoint type")
 
+	// ErrEndpointWithInvalidAddress is the error with which Gatus will panic if an endpoint other than http, dns, icmp or websocket has no host:port
+	ErrEndpointWithInvalidAddress = errors.New("endpoints other than http, dns, icmp and websocket must have an url in the format <scheme>://<host>:<port>")
+
 	// ErrInvalidConditionFormat is the error with which Gatus will panic if a condition has an invalid format
 	ErrInvalidConditionFormat = errors.New("invalid condition format: does not match '<VALUE> <COMPARATOR> <VALUE>'")
 
+	// ErrEndpointWithInvalidInterval is the error with which Gatus will panic if an endpoint has a negative interval
+	ErrEndpointWithInvalidInterval = errors.New("endpoint interval must not be negative")
+
 	// ErrInvalidVersionFormat is the error with which version not match Semantic Versions
 	ErrInvalidVersionFormat = errors.New("invalid condition format: does not match '<VALUE> <COMPARATOR> <VALUE>'")
 )
@@ -74,10 +94,25 @@ type Endpoint struct {
 	// DNS is the configuration of DNS monitoring
 	DNS *DNS `yaml:"dns,omitempty"`
 
+	// ICMP is the configuration of ICMP monitoring
+	ICMP *ICMP `yaml:"icmp,omitempty"`
+
+	// GRPC is the configuration of gRPC health checking
+	GRPC *GRPC `yaml:"grpc,omitempty"`
+
+	// SSH is the configuration of SSH monitoring
+	SSH *SSH `yaml:"ssh,omitempty"`
+
+	// Mail is the configuration of SMTP, IMAP and POP3 monitoring
+	Mail *Mail `yaml:"mail,omitempty"`
+
+	// Database is the configuration of Postgres, MySQL and Redis monitoring
+	Database *Database `yaml:"database,omitempty"`
+
 	// Method of the request made to the url of the endpoint
 	Method string `yaml:"method,omitempty"`
 
-	// Body of the request
+	// Body of the request, or the message sent to a websocket endpoint
 	Body string `yaml:"body,omitempty"`
 
 	// GraphQL is whether to wrap the body in a query param ({"query":"$body"})
@@ -89,8 +124,31 @@ type Endpoint struct {
 	// Version of Current Release | Package
 	Version string `yaml:"version,omitempty"`
 
+	// Interval is the minimum duration between two evaluations of the endpoint.
+	// If not set, the endpoint is evaluated every time a check is triggered.
+	Interval time.Duration `yaml:"interval,omitempty"`
+
+	// SLAMode overrides how the SLA of the endpoint is computed. Defaults to the global SLA mode.
+	SLAMode SLAMode `yaml:"sla-mode,omitempty"`
+
 	// Conditions used to determine the health of the endpoint
 	Conditions []Condition `yaml:"conditions"`
+
+	// Alerts is the alerting configuration for the endpoint in case of failure
+	Alerts []*alert.Alert `yaml:"alerts,omitempty"`
+
+	// NumberOfFailuresInARow is the number of unsuccessful evaluations in a row
+	NumberOfFailuresInARow int `yaml:"-"`
+
+	// NumberOfSuccessesInARow is the number of successful evaluations in a row
+	NumberOfSuccessesInARow int `yaml:"-"`
+
+	// compiledConditions are the evaluators of the Conditions, compiled once by ValidateAndSetDefaults
+	compiledConditions []conditionExpression
+
+	// conditionsNeedBody and conditionsNeedIP cache whether one of the Conditions has a BodyPlaceholder or an
+	// IPPlaceholder. They are only set once the Conditions are compiled.
+	conditionsNeedBody, conditionsNeedIP bool
 }
 
 // IsEnabled returns whether the endpoint is enabled or not
@@ -111,6 +169,32 @@ func (endpoint Endpoint) Type() EndpointType {
 			return EndpointTypeVERSION
 		}
 		return EndpointTypeHTTP
+	case strings.HasPrefix(endpoint.URL, "tcp://"):
+		return EndpointTypeTCP
+	case strings.HasPrefix(endpoint.URL, "udp://"):
+		return EndpointTypeUDP
+	case strings.HasPrefix(endpoint.URL, "tls://"):
+		return EndpointTypeTLS
+	case strings.HasPrefix(endpoint.URL, "icmp://"):
+		return EndpointTypeICMP
+	case strings.HasPrefix(endpoint.URL, "grpc://"):
+		return EndpointTypeGRPC
+	case strings.HasPrefix(endpoint.URL, "ws://") || strings.HasPrefix(endpoint.URL, "wss://"):
+		return EndpointTypeWS
+	case strings.HasPrefix(endpoint.URL, "ssh://"):
+		return EndpointTypeSSH
+	case strings.HasPrefix(endpoint.URL, "smtp://"):
+		return EndpointTypeSMTP
+	case strings.HasPrefix(endpoint.URL, "imap://"):
+		return EndpointTypeIMAP
+	case strings.HasPrefix(endpoint.URL, "pop3://"):
+		return EndpointTypePOP3
+	case strings.HasPrefix(endpoint.URL, "postgres://"):
+		return EndpointTypePOSTGRES
+	case strings.HasPrefix(endpoint.URL, "mysql://"):
+		return EndpointTypeMYSQL
+	case strings.HasPrefix(endpoint.URL, "redis://"):
+		return EndpointTypeREDIS
 	default:
 		return EndpointTypeUNKNOWN
 	}
@@ -142,24 +226,98 @@ func (endpoint *Endpoint) ValidateAndSetDefaults() error {
 	if len(endpoint.URL) == 0 {
 		return ErrEndpointWithNoURL
 	}
+	if endpoint.Interval < 0 {
+		return ErrEndpointWithInvalidInterval
+	}
+	if len(endpoint.SLAMode) == 0 {
+		endpoint.SLAMode = SLAModeCondition
+	}
+	if err := endpoint.SLAMode.Validate(); err != nil {
+		return err
+	}
 	if len(endpoint.Conditions) == 0 {
 		return ErrEndpointWithNoCondition
 	}
+	endpoint.compiledConditions = make([]conditionExpression, 0, len(endpoint.Conditions))
 	for _, c := range endpoint.Conditions {
-		if err := c.Validate(); err != nil {
+		expression, err := c.compileAndValidate()
+		if err != nil {
 			return fmt.Errorf("%v: %w", ErrInvalidConditionFormat, err)
 		}
+		endpoint.compiledConditions = append(endpoint.compiledConditions, expression)
+		endpoint.conditionsNeedBody = endpoint.conditionsNeedBody || c.hasBodyPlaceholder()
+		endpoint.conditionsNeedIP = endpoint.conditionsNeedIP || c.hasIPPlaceholder()
+	}
+	for _, endpointAlert := range endpoint.Alerts {
+		if err := endpointAlert.ValidateAndSetDefaults(); err != nil {
+			return err
+		}
 	}
 	if endpoint.DNS != nil {
-		return endpoint.DNS.validateAndSetDefault()
+		if err := endpoint.DNS.validateAndSetDefault(endpoint.URL); err != nil {
+			return err
+		}
 	}
 	if endpoint.Type() == EndpointTypeVERSION {
 		if _, err := semver.NewVersion(endpoint.Version); err != nil {
 			return fmt.Errorf("%v: %w", ErrInvalidVersionFormat, err)
 		}
 	}
-	if endpoint.Type() == EndpointTypeUNKNOWN {
+	switch endpoint.Type() {
+	case EndpointTypeUNKNOWN:
 		return ErrUnknownEndpointType
+	case EndpointTypeDNS:
+		// The url of a DNS endpoint is the address of the server, which was validated along with the query
+		return nil
+	case EndpointTypeTCP, EndpointTypeUDP, EndpointTypeTLS:
+		if _, _, err := net.SplitHostPort(endpoint.address()); err != nil {
+			return fmt.Errorf("%w: %v", ErrEndpointWithInvalidAddress, err)
+		}
+		return nil
+	case EndpointTypeGRPC:
+		if _, _, err := net.SplitHostPort(endpoint.address()); err != nil {
+			return fmt.Errorf("%w: %v", ErrEndpointWithInvalidAddress, err)
+		}
+		if endpoint.GRPC == nil {
+			endpoint.GRPC = &GRPC{}
+		}
+		return nil
+	case EndpointTypeSSH:
+		if _, _, err := net.SplitHostPort(endpoint.address()); err != nil {
+			return fmt.Errorf("%w: %v", ErrEndpointWithInvalidAddress, err)
+		}
+		if endpoint.SSH == nil {
+			endpoint.SSH = &SSH{}
+		}
+		return endpoint.SSH.validateAndSetDefault()
+	case EndpointTypeSMTP, EndpointTypeIMAP, EndpointTypePOP3:
+		if _, _, err := net.SplitHostPort(endpoint.address()); err != nil {
+			return fmt.Errorf("%w: %v", ErrEndpointWithInvalidAddress, err)
+		}
+		if endpoint.Mail == nil {
+			endpoint.Mail = &Mail{}
+		}
+		return nil
+	case EndpointTypePOSTGRES, EndpointTypeMYSQL, EndpointTypeREDIS:
+		urlObject, err := url.Parse(endpoint.URL)
+		if err != nil {
+			return err
+		}
+		if urlObject.User != nil {
+			return ErrDatabaseWithInlineCredentials
+		}
+		if _, _, err := net.SplitHostPort(urlObject.Host); err != nil {
+			return fmt.Errorf("%w: %v", ErrEndpointWithInvalidAddress, err)
+		}
+		if endpoint.Database == nil {
+			endpoint.Database = &Database{}
+		}
+		return nil
+	case EndpointTypeICMP:
+		if endpoint.ICMP == nil {
+			endpoint.ICMP = &ICMP{}
+		}
+		return endpoint.ICMP.validateAndSetDefault()
 	}
 	// Make sure that the request can be created
 	_, err := http.NewRequest(endpoint.Method, endpoint.URL, bytes.NewBuffer([]byte(endpoint.Body)))
@@ -183,8 +341,11 @@ func (endpoint Endpoint) Key() string {
 func (endpoint *Endpoint) EvaluateHealth() *Result {
 	result := &Result{Success: true, Errors: []string{}}
 	// Parse or extract hostname from URL
-	if endpoint.DNS != nil {
-		result.Hostname = strings.TrimSuffix(endpoint.URL, ":53")
+	if endpoint.DNS != nil && endpoint.DNS.Transport != DNSTransportHTTPS {
+		result.Hostname = endpoint.URL
+		if host, _, err := net.SplitHostPort(endpoint.URL); err == nil {
+			result.Hostname = host
+		}
 	} else {
 		urlObject, err := url.Parse(endpoint.URL)
 		if err != nil {
@@ -207,8 +368,14 @@ func (endpoint *Endpoint) EvaluateHealth() *Result {
 		result.Success = false
 	}
 	// Evaluate the conditions
-	for _, condition := range endpoint.Conditions {
-		success := condition.evaluate(result, false)
+	for i, condition := range endpoint.Conditions {
+		var success bool
+		if i < len(endpoint.compiledConditions) {
+			success = evaluateConditionExpression(endpoint.compiledConditions[i], result, false)
+		} else {
+			// The endpoint has not been validated, so the condition must be parsed
+			success = condition.evaluate(result, false)
+		}
 		if !success {
 			result.Success = false
 		}
@@ -236,10 +403,48 @@ func (endpoint *Endpoint) call(result *Result) {
 		request = endpoint.buildHTTPRequest()
 	}
 	startTime := time.Now()
-	if endpointType == EndpointTypeDNS {
+	switch endpointType {
+	case EndpointTypeDNS:
 		endpoint.DNS.query(endpoint.URL, result)
 		result.Duration = time.Since(startTime)
-	} else {
+	case EndpointTypeTCP:
+		result.Connected = util.CanCreateTCPConnection(endpoint.address())
+		result.Duration = time.Since(startTime)
+	case EndpointTypeUDP:
+		result.Connected = util.CanCreateUDPConnection(endpoint.address())
+		result.Duration = time.Since(startTime)
+	case EndpointTypeTLS:
+		result.Connected, certificate, err = util.CanPerformTLS(endpoint.address(), nil)
+		result.Duration = time.Since(startTime)
+		if err != nil {
+			result.AddError(err.Error())
+			return
+		}
+		result.CertificateExpiration = time.Until(certificate.NotAfter)
+	case EndpointTypeICMP:
+		endpoint.ICMP.ping(result.Hostname, result)
+		if !result.Connected {
+			result.Duration = time.Since(startTime)
+		}
+	case EndpointTypeGRPC:
+		endpoint.GRPC.check(endpoint.address(), result)
+		result.Duration = time.Since(startTime)
+	case EndpointTypeSSH:
+		endpoint.SSH.check(endpoint.address(), result)
+		result.Duration = time.Since(startTime)
+	case EndpointTypeSMTP, EndpointTypeIMAP, EndpointTypePOP3:
+		endpoint.Mail.probe(endpointType, endpoint.address(), result)
+		result.Duration = time.Since(startTime)
+	case EndpointTypePOSTGRES, EndpointTypeMYSQL, EndpointTypeREDIS:
+		endpoint.Database.probe(endpointType, endpoint.URL, result)
+		result.Duration = time.Since(startTime)
+	case EndpointTypeWS:
+		result.Connected, result.Body, err = util.QueryWebSocket(endpoint.URL, endpoint.Body, endpoint.Headers)
+		result.Duration = time.Since(startTime)
+		if err != nil {
+			result.AddError(err.Error())
+		}
+	default:
 		var retry = 0
 		for retry < 3 {
 			response, err = util.GetHTTPClient().Do(request)
@@ -279,6 +484,12 @@ func (endpoint *Endpoint) Close() {
 	}
 }
 
+// address returns the host:port of an endpoint whose url is in the format <scheme>://<host>:<port>
+func (endpoint *Endpoint) address() string {
+	_, address, _ := strings.Cut(endpoint.URL, "://")
+	return address
+}
+
 func (endpoint *Endpoint) buildHTTPRequest() *http.Request {
 	var bodyBuffer *bytes.Buffer
 	if endpoint.GraphQL {
@@ -302,6 +513,9 @@ func (endpoint *Endpoint) buildHTTPRequest() *http.Request {
 
 // needsToReadBody checks if there's any condition that requires the response Body to be read
 func (endpoint *Endpoint) needsToReadBody() bool {
+	if endpoint.compiledConditions != nil {
+		return endpoint.conditionsNeedBody
+	}
 	for _, condition := range endpoint.Conditions {
 		if condition.hasBodyPlaceholder() {
 			return true
@@ -312,6 +526,9 @@ func (endpoint *Endpoint) needsToReadBody() bool {
 
 // needsToRetrieveIP checks if there's any condition that requires an IP lookup
 func (endpoint *Endpoint) needsToRetrieveIP() bool {
+	if endpoint.compiledConditions != nil {
+		return endpoint.conditionsNeedIP
+	}
 	for _, condition := range endpoint.Conditions {
 		if condition.hasIPPlaceholder() {
 			return true
@@ -0,0 +1,139 @@
+package core
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/websocket"
+)
+
+func TestEndpoint_Type(t *testing.T) {
+	scenarios := []struct {
+		endpoint     Endpoint
+		expectedType EndpointType
+	}{
+		{endpoint: Endpoint{URL: "8.8.8.8", DNS: &DNS{QueryType: "A", QueryName: "example.com"}}, expectedType: EndpointTypeDNS},
+		{endpoint: Endpoint{URL: "https://example.org/health"}, expectedType: EndpointTypeHTTP},
+		{endpoint: Endpoint{URL: "https://example.org/version", Version: "1.2.3"}, expectedType: EndpointTypeVERSION},
+		{endpoint: Endpoint{URL: "tcp://127.0.0.1:6379"}, expectedType: EndpointTypeTCP},
+		{endpoint: Endpoint{URL: "udp://127.0.0.1:53"}, expectedType: EndpointTypeUDP},
+		{endpoint: Endpoint{URL: "tls://example.org:443"}, expectedType: EndpointTypeTLS},
+		{endpoint: Endpoint{URL: "grpc://127.0.0.1:50051"}, expectedType: EndpointTypeGRPC},
+		{endpoint: Endpoint{URL: "ws://example.org/gateway"}, expectedType: EndpointTypeWS},
+		{endpoint: Endpoint{URL: "wss://example.org/gateway"}, expectedType: EndpointTypeWS},
+		{endpoint: Endpoint{URL: "ssh://127.0.0.1:22"}, expectedType: EndpointTypeSSH},
+		{endpoint: Endpoint{URL: "smtp://127.0.0.1:25"}, expectedType: EndpointTypeSMTP},
+		{endpoint: Endpoint{URL: "imap://127.0.0.1:143"}, expectedType: EndpointTypeIMAP},
+		{endpoint: Endpoint{URL: "pop3://127.0.0.1:110"}, expectedType: EndpointTypePOP3},
+		{endpoint: Endpoint{URL: "ftp://example.org"}, expectedType: EndpointTypeUNKNOWN},
+	}
+	for _, scenario := range scenarios {
+		t.Run(string(scenario.expectedType)+"-"+scenario.endpoint.URL, func(t *testing.T) {
+			if endpointType := scenario.endpoint.Type(); endpointType != scenario.expectedType {
+				t.Errorf("expected %s, got %s", scenario.expectedType, endpointType)
+			}
+		})
+	}
+}
+
+func TestEndpoint_ValidateAndSetDefaultsWithConnectionTypes(t *testing.T) {
+	scenarios := []struct {
+		url           string
+		expectedError error
+	}{
+		{url: "tcp://127.0.0.1:6379", expectedError: nil},
+		{url: "udp://127.0.0.1:53", expectedError: nil},
+		{url: "tls://example.org:443", expectedError: nil},
+		{url: "tcp://127.0.0.1", expectedError: ErrEndpointWithInvalidAddress},
+		{url: "tls://example.org", expectedError: ErrEndpointWithInvalidAddress},
+		{url: "smtp://example.org", expectedError: ErrEndpointWithInvalidAddress},
+		{url: "ftp://example.org", expectedError: ErrUnknownEndpointType},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.url, func(t *testing.T) {
+			endpoint := Endpoint{Name: "test", URL: scenario.url, Conditions: []Condition{"[CONNECTED] == true"}}
+			err := endpoint.ValidateAndSetDefaults()
+			if !errors.Is(err, scenario.expectedError) {
+				t.Errorf("expected error %v, got %v", scenario.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestEndpoint_EvaluateHealthWithTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	endpoint := Endpoint{Name: "tcp", URL: "tcp://" + listener.Addr().String(), Conditions: []Condition{"[CONNECTED] == true", "[RESPONSE_TIME] < 1000"}}
+	if err := endpoint.ValidateAndSetDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	result := endpoint.EvaluateHealth()
+	if !result.Success || !result.Connected {
+		t.Errorf("expected a successful result, got errors %v and condition results %v", result.Errors, result.ConditionResults)
+	}
+	listener.Close()
+	if result = endpoint.EvaluateHealth(); result.Success || result.Connected {
+		t.Error("expected the result to fail once the listener is closed")
+	}
+}
+
+func TestEndpoint_EvaluateHealthWithUDP(t *testing.T) {
+	endpoint := Endpoint{Name: "udp", URL: "udp://127.0.0.1:53", Conditions: []Condition{"[CONNECTED] == true"}}
+	if err := endpoint.ValidateAndSetDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	if result := endpoint.EvaluateHealth(); !result.Success {
+		t.Errorf("expected a successful result, got errors %v", result.Errors)
+	}
+}
+
+func TestEndpoint_EvaluateHealthWithTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	endpoint := Endpoint{Name: "tls", URL: "tls://" + strings.TrimPrefix(server.URL, "https://"), Conditions: []Condition{"[CONNECTED] == true"}}
+	if err := endpoint.ValidateAndSetDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	result := endpoint.EvaluateHealth()
+	if result.Success || result.Connected {
+		t.Error("expected the self-signed certificate to be rejected")
+	}
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0], "certificate") {
+		t.Errorf("expected a certificate error, got %v", result.Errors)
+	}
+}
+
+func TestEndpoint_EvaluateHealthWithWebSocket(t *testing.T) {
+	server := httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		var message string
+		if err := websocket.Message.Receive(ws, &message); err != nil {
+			return
+		}
+		_ = websocket.Message.Send(ws, `{"type":"pong","clients":3}`)
+	}))
+	defer server.Close()
+	endpoint := Endpoint{
+		Name:       "websocket",
+		URL:        "ws" + strings.TrimPrefix(server.URL, "http"),
+		Body:       `{"type":"ping"}`,
+		Conditions: []Condition{"[CONNECTED] == true", "[BODY].type == pong", "[BODY].clients > 0"},
+	}
+	if err := endpoint.ValidateAndSetDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	result := endpoint.EvaluateHealth()
+	if !result.Success {
+		t.Errorf("expected a successful result, got errors %v and condition results %v", result.Errors, result.ConditionResults)
+	}
+	server.Close()
+	if result = endpoint.EvaluateHealth(); result.Success || result.Connected || len(result.Errors) == 0 {
+		t.Error("expected the result to fail once the server is closed")
+	}
+}
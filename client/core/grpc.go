@@ -0,0 +1,64 @@
+package core
+
+import (
+	"context"
+	"crypto/tls"
+
+	"github.com/serverless-aliyun/func-status/client/util"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCStatusServiceUnknown is the serving status reported when the server does not know the requested service
+const GRPCStatusServiceUnknown = "SERVICE_UNKNOWN"
+
+// GRPC is the configuration for a Endpoint of type GRPC
+type GRPC struct {
+	// Service is the name of the service whose health is checked. If empty, the health of the server as a whole is checked.
+	Service string `yaml:"service,omitempty"`
+
+	// TLS is whether to connect to the server over TLS, in which case the certificate is verified with the system's root CAs
+	TLS bool `yaml:"tls,omitempty"`
+
+	// tlsConfig overrides the TLS configuration used when TLS is enabled
+	tlsConfig *tls.Config
+}
+
+// check calls grpc.health.v1.Health/Check on the server at the given address.
+//
+// A server answering NOT_FOUND, which is what the reference implementation does for an unknown service, is
+// considered connected and reported as SERVICE_UNKNOWN.
+func (g *GRPC) check(address string, result *Result) {
+	ctx, cancel := context.WithTimeout(context.Background(), util.ConnectionTimeout)
+	defer cancel()
+	transportCredentials := insecure.NewCredentials()
+	if g.TLS {
+		tlsConfig := g.tlsConfig
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		}
+		transportCredentials = credentials.NewTLS(tlsConfig)
+	}
+	conn, err := grpc.DialContext(ctx, address, grpc.WithTransportCredentials(transportCredentials))
+	if err != nil {
+		result.AddError(err.Error())
+		return
+	}
+	defer conn.Close()
+	response, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: g.Service})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			result.Connected = true
+			result.GRPCStatus = GRPCStatusServiceUnknown
+			return
+		}
+		result.AddError(err.Error())
+		return
+	}
+	result.Connected = true
+	result.GRPCStatus = response.GetStatus().String()
+}
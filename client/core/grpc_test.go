@@ -0,0 +1,94 @@
+package core
+
+import (
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func startHealthServer(t *testing.T) (string, *health.Server) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	healthServer := health.NewServer()
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return listener.Addr().String(), healthServer
+}
+
+func TestEndpoint_EvaluateHealthWithGRPC(t *testing.T) {
+	address, healthServer := startHealthServer(t)
+	healthServer.SetServingStatus("orders", healthpb.HealthCheckResponse_NOT_SERVING)
+	scenarios := []struct {
+		name              string
+		grpc              *GRPC
+		expectedConnected bool
+		expectedStatus    string
+	}{
+		{
+			name:              "server",
+			grpc:              nil,
+			expectedConnected: true,
+			expectedStatus:    "SERVING",
+		},
+		{
+			name:              "not-serving-service",
+			grpc:              &GRPC{Service: "orders"},
+			expectedConnected: true,
+			expectedStatus:    "NOT_SERVING",
+		},
+		{
+			name:              "unknown-service",
+			grpc:              &GRPC{Service: "payments"},
+			expectedConnected: true,
+			expectedStatus:    GRPCStatusServiceUnknown,
+		},
+		{
+			name:              "tls-against-plaintext-server",
+			grpc:              &GRPC{TLS: true},
+			expectedConnected: false,
+			expectedStatus:    "",
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			endpoint := Endpoint{Name: "grpc", URL: "grpc://" + address, GRPC: scenario.grpc, Conditions: []Condition{"[GRPC_STATUS] == SERVING", "[RESPONSE_TIME] < 1000"}}
+			if err := endpoint.ValidateAndSetDefaults(); err != nil {
+				t.Fatal(err)
+			}
+			result := endpoint.EvaluateHealth()
+			if result.Connected != scenario.expectedConnected {
+				t.Errorf("expected connected to be %v, got %v with errors %v", scenario.expectedConnected, result.Connected, result.Errors)
+			}
+			if result.GRPCStatus != scenario.expectedStatus {
+				t.Errorf("expected status %q, got %q", scenario.expectedStatus, result.GRPCStatus)
+			}
+			if expectedSuccess := scenario.expectedStatus == "SERVING"; result.Success != expectedSuccess {
+				t.Errorf("expected success to be %v, got %v", expectedSuccess, result.Success)
+			}
+			if !scenario.expectedConnected && len(result.Errors) == 0 {
+				t.Error("expected an error to be reported")
+			}
+		})
+	}
+}
+
+func TestEndpoint_ValidateAndSetDefaultsWithGRPC(t *testing.T) {
+	endpoint := Endpoint{Name: "grpc", URL: "grpc://example.org", Conditions: []Condition{"[CONNECTED] == true"}}
+	if err := endpoint.ValidateAndSetDefaults(); err == nil {
+		t.Error("expected an error for a grpc endpoint without a port")
+	}
+	endpoint.URL = "grpc://example.org:50051"
+	if err := endpoint.ValidateAndSetDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	if endpoint.Type() != EndpointTypeGRPC || endpoint.GRPC == nil {
+		t.Errorf("expected a grpc endpoint with a default configuration, got %s and %+v", endpoint.Type(), endpoint.GRPC)
+	}
+}
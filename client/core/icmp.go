@@ -0,0 +1,150 @@
+package core
+
+import (
+	"errors"
+	"net"
+	"os"
+	"time"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+var (
+	// ErrICMPWithInvalidCount is the error with which Gatus will panic if an icmp endpoint is configured with a negative count
+	ErrICMPWithInvalidCount = errors.New("icmp count must not be negative")
+
+	// ErrICMPWithInvalidInterval is the error with which Gatus will panic if an icmp endpoint is configured with a negative interval or timeout
+	ErrICMPWithInvalidInterval = errors.New("icmp interval and timeout must not be negative")
+)
+
+const (
+	// DefaultICMPCount is the default number of echo requests sent per evaluation
+	DefaultICMPCount = 3
+
+	// DefaultICMPInterval is the default duration between two echo requests
+	DefaultICMPInterval = 200 * time.Millisecond
+
+	// DefaultICMPTimeout is the default duration to wait for each echo reply
+	DefaultICMPTimeout = 2 * time.Second
+
+	icmpProtocolIPv4 = 1
+	icmpProtocolIPv6 = 58
+)
+
+// ICMP is the configuration for a Endpoint of type ICMP
+type ICMP struct {
+	// Count is the number of echo requests sent per evaluation
+	Count int `yaml:"count,omitempty"`
+
+	// Interval is the duration between two echo requests
+	Interval time.Duration `yaml:"interval,omitempty"`
+
+	// Timeout is the duration to wait for each echo reply
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+func (i *ICMP) validateAndSetDefault() error {
+	if i.Count < 0 {
+		return ErrICMPWithInvalidCount
+	}
+	if i.Interval < 0 || i.Timeout < 0 {
+		return ErrICMPWithInvalidInterval
+	}
+	if i.Count == 0 {
+		i.Count = DefaultICMPCount
+	}
+	if i.Interval == 0 {
+		i.Interval = DefaultICMPInterval
+	}
+	if i.Timeout == 0 {
+		i.Timeout = DefaultICMPTimeout
+	}
+	return nil
+}
+
+// ping sends echo requests to the host through an unprivileged ICMP datagram socket.
+//
+// On Linux, the group of the process must be allowed by the net.ipv4.ping_group_range sysctl.
+func (i *ICMP) ping(host string, result *Result) {
+	ip, err := net.ResolveIPAddr("ip", host)
+	if err != nil {
+		result.AddError(err.Error())
+		return
+	}
+	network, address, protocol := "udp4", "0.0.0.0", icmpProtocolIPv4
+	var requestType, replyType icmp.Type = ipv4.ICMPTypeEcho, ipv4.ICMPTypeEchoReply
+	if ip.IP.To4() == nil {
+		network, address, protocol = "udp6", "::", icmpProtocolIPv6
+		requestType, replyType = ipv6.ICMPTypeEchoRequest, ipv6.ICMPTypeEchoReply
+	}
+	conn, err := icmp.ListenPacket(network, address)
+	if err != nil {
+		result.AddError(err.Error())
+		return
+	}
+	defer conn.Close()
+	destination := &net.UDPAddr{IP: ip.IP, Zone: ip.Zone}
+	id := os.Getpid() & 0xffff
+	var rtts []time.Duration
+	buffer := make([]byte, 1500)
+	for seq := 0; seq < i.Count; seq++ {
+		if seq > 0 {
+			time.Sleep(i.Interval)
+		}
+		request, _ := (&icmp.Message{
+			Type: requestType,
+			Body: &icmp.Echo{ID: id, Seq: seq, Data: []byte("func-status")},
+		}).Marshal(nil)
+		sentAt := time.Now()
+		if _, err := conn.WriteTo(request, destination); err != nil {
+			result.AddError(err.Error())
+			continue
+		}
+		_ = conn.SetReadDeadline(sentAt.Add(i.Timeout))
+		for {
+			n, _, err := conn.ReadFrom(buffer)
+			if err != nil {
+				// The reply was lost or the deadline was exceeded
+				break
+			}
+			reply, err := icmp.ParseMessage(protocol, buffer[:n])
+			if err != nil || reply.Type != replyType {
+				continue
+			}
+			// The kernel rewrites the identifier of unprivileged echo requests, so only the sequence is matched
+			if echo, ok := reply.Body.(*icmp.Echo); ok && echo.Seq == seq {
+				rtts = append(rtts, time.Since(sentAt))
+				break
+			}
+		}
+	}
+	summarizePings(result, i.Count, rtts)
+}
+
+// summarizePings fills the result with the outcome of the echo requests sent
+func summarizePings(result *Result, sent int, rtts []time.Duration) {
+	if sent > 0 {
+		result.PacketLoss = float64(sent-len(rtts)) * 100 / float64(sent)
+	}
+	result.Connected = len(rtts) > 0
+	if len(rtts) == 0 {
+		return
+	}
+	var total, variation time.Duration
+	for j, rtt := range rtts {
+		total += rtt
+		if j > 0 {
+			difference := rtt - rtts[j-1]
+			if difference < 0 {
+				difference = -difference
+			}
+			variation += difference
+		}
+	}
+	result.Duration = total / time.Duration(len(rtts))
+	if len(rtts) > 1 {
+		result.Jitter = variation / time.Duration(len(rtts)-1)
+	}
+}
@@ -0,0 +1,124 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestICMP_validateAndSetDefault(t *testing.T) {
+	scenarios := []struct {
+		name          string
+		icmp          ICMP
+		expectedICMP  ICMP
+		expectedError error
+	}{
+		{
+			name:         "defaults",
+			icmp:         ICMP{},
+			expectedICMP: ICMP{Count: DefaultICMPCount, Interval: DefaultICMPInterval, Timeout: DefaultICMPTimeout},
+		},
+		{
+			name:         "custom",
+			icmp:         ICMP{Count: 5, Interval: time.Second, Timeout: 3 * time.Second},
+			expectedICMP: ICMP{Count: 5, Interval: time.Second, Timeout: 3 * time.Second},
+		},
+		{
+			name:          "negative-count",
+			icmp:          ICMP{Count: -1},
+			expectedError: ErrICMPWithInvalidCount,
+		},
+		{
+			name:          "negative-timeout",
+			icmp:          ICMP{Timeout: -time.Second},
+			expectedError: ErrICMPWithInvalidInterval,
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			err := scenario.icmp.validateAndSetDefault()
+			if err != scenario.expectedError {
+				t.Fatalf("expected error %v, got %v", scenario.expectedError, err)
+			}
+			if err == nil && scenario.icmp != scenario.expectedICMP {
+				t.Errorf("expected %+v, got %+v", scenario.expectedICMP, scenario.icmp)
+			}
+		})
+	}
+}
+
+func TestSummarizePings(t *testing.T) {
+	scenarios := []struct {
+		name               string
+		sent               int
+		rtts               []time.Duration
+		expectedConnected  bool
+		expectedPacketLoss float64
+		expectedDuration   time.Duration
+		expectedJitter     time.Duration
+	}{
+		{
+			name:               "all-lost",
+			sent:               3,
+			rtts:               nil,
+			expectedConnected:  false,
+			expectedPacketLoss: 100,
+		},
+		{
+			name:               "single-reply",
+			sent:               1,
+			rtts:               []time.Duration{10 * time.Millisecond},
+			expectedConnected:  true,
+			expectedPacketLoss: 0,
+			expectedDuration:   10 * time.Millisecond,
+		},
+		{
+			name:               "one-lost",
+			sent:               4,
+			rtts:               []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 15 * time.Millisecond},
+			expectedConnected:  true,
+			expectedPacketLoss: 25,
+			expectedDuration:   15 * time.Millisecond,
+			expectedJitter:     7500 * time.Microsecond,
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			result := &Result{}
+			summarizePings(result, scenario.sent, scenario.rtts)
+			if result.Connected != scenario.expectedConnected {
+				t.Errorf("expected connected to be %v", scenario.expectedConnected)
+			}
+			if result.PacketLoss != scenario.expectedPacketLoss {
+				t.Errorf("expected packet loss %v, got %v", scenario.expectedPacketLoss, result.PacketLoss)
+			}
+			if result.Duration != scenario.expectedDuration {
+				t.Errorf("expected duration %v, got %v", scenario.expectedDuration, result.Duration)
+			}
+			if result.Jitter != scenario.expectedJitter {
+				t.Errorf("expected jitter %v, got %v", scenario.expectedJitter, result.Jitter)
+			}
+		})
+	}
+}
+
+func TestEndpoint_EvaluateHealthWithICMP(t *testing.T) {
+	endpoint := Endpoint{
+		Name:       "icmp",
+		URL:        "icmp://127.0.0.1",
+		ICMP:       &ICMP{Count: 2, Interval: 10 * time.Millisecond, Timeout: time.Second},
+		Conditions: []Condition{"[CONNECTED] == true", "[PACKET_LOSS] == 0"},
+	}
+	if err := endpoint.ValidateAndSetDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	result := endpoint.EvaluateHealth()
+	for _, err := range result.Errors {
+		if strings.Contains(err, "permission denied") || strings.Contains(err, "not permitted") {
+			t.Skip("unprivileged ICMP sockets are not allowed, see net.ipv4.ping_group_range:", err)
+		}
+	}
+	if !result.Success {
+		t.Errorf("expected a successful result, got errors %v and condition results %v", result.Errors, result.ConditionResults)
+	}
+}
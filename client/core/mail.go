@@ -0,0 +1,138 @@
+package core
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/serverless-aliyun/func-status/client/util"
+)
+
+const (
+	// MailStatusReady is the status reported when a mail server greets with a positive reply.
+	//
+	// SMTP greetings carry their own reply code, but IMAP and POP3 greetings do not, so they are mapped to the code
+	// an SMTP server uses to signal that it is ready.
+	MailStatusReady = 220
+
+	// MailStatusNotAvailable is the status reported when an IMAP or POP3 server greets with a negative reply
+	MailStatusNotAvailable = 421
+)
+
+// Mail is the configuration for a Endpoint of type SMTP, IMAP or POP3
+type Mail struct {
+	// StartTLS is whether to upgrade the connection with STARTTLS after the greeting
+	StartTLS bool `yaml:"starttls,omitempty"`
+
+	// tlsConfig overrides the TLS configuration used for STARTTLS
+	tlsConfig *tls.Config
+}
+
+// probe connects to the mail server at the given address, reads its greeting and, if configured, negotiates STARTTLS
+func (m *Mail) probe(endpointType EndpointType, address string, result *Result) {
+	conn, err := net.DialTimeout("tcp", address, util.ConnectionTimeout)
+	if err != nil {
+		result.AddError(err.Error())
+		return
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(util.ConnectionTimeout))
+	text := textproto.NewConn(conn)
+	result.Connected = true
+	switch endpointType {
+	case EndpointTypeSMTP:
+		result.HTTPStatus, _, err = text.ReadResponse(0)
+	default:
+		result.HTTPStatus, err = readMailGreeting(endpointType, text)
+	}
+	if err != nil {
+		result.AddError(err.Error())
+		return
+	}
+	if !m.StartTLS || result.HTTPStatus != MailStatusReady {
+		return
+	}
+	if err := startTLS(endpointType, text); err != nil {
+		result.AddError(err.Error())
+		return
+	}
+	tlsConfig := m.tlsConfig
+	if tlsConfig == nil {
+		host, _, _ := net.SplitHostPort(address)
+		tlsConfig = &tls.Config{ServerName: host}
+	}
+	tlsConn := tls.Client(conn, tlsConfig)
+	if err := tlsConn.Handshake(); err != nil {
+		result.AddError(err.Error())
+		return
+	}
+	if certificates := tlsConn.ConnectionState().PeerCertificates; len(certificates) > 0 {
+		result.CertificateExpiration = time.Until(certificates[0].NotAfter)
+	}
+}
+
+// readMailGreeting reads the greeting of an IMAP or POP3 server and maps it to a status
+func readMailGreeting(endpointType EndpointType, text *textproto.Conn) (int, error) {
+	line, err := text.ReadLine()
+	if err != nil {
+		return 0, err
+	}
+	positive := strings.HasPrefix(line, "+OK")
+	if endpointType == EndpointTypeIMAP {
+		positive = strings.HasPrefix(line, "* OK") || strings.HasPrefix(line, "* PREAUTH")
+	}
+	if positive {
+		return MailStatusReady, nil
+	}
+	return MailStatusNotAvailable, nil
+}
+
+// startTLS asks the server to upgrade the connection to TLS
+func startTLS(endpointType EndpointType, text *textproto.Conn) error {
+	switch endpointType {
+	case EndpointTypeSMTP:
+		if _, err := text.Cmd("EHLO localhost"); err != nil {
+			return err
+		}
+		if _, _, err := text.ReadResponse(250); err != nil {
+			return err
+		}
+		if _, err := text.Cmd("STARTTLS"); err != nil {
+			return err
+		}
+		_, _, err := text.ReadResponse(220)
+		return err
+	case EndpointTypeIMAP:
+		if _, err := text.Cmd("a1 STARTTLS"); err != nil {
+			return err
+		}
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return err
+			}
+			// Untagged responses may precede the tagged completion
+			if strings.HasPrefix(line, "a1 ") {
+				if !strings.HasPrefix(line, "a1 OK") {
+					return fmt.Errorf("STARTTLS refused: %s", line)
+				}
+				return nil
+			}
+		}
+	default:
+		if _, err := text.Cmd("STLS"); err != nil {
+			return err
+		}
+		line, err := text.ReadLine()
+		if err != nil {
+			return err
+		}
+		if !strings.HasPrefix(line, "+OK") {
+			return fmt.Errorf("STLS refused: %s", line)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,162 @@
+package core
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startMailServer starts a server that sends the greeting, answers the commands leading to STARTTLS with the given
+// replies and then performs a TLS handshake with the given configuration
+func startMailServer(t *testing.T, greeting string, replies map[string]string, tlsConfig *tls.Config) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = conn.Write([]byte(greeting + "\r\n"))
+				reader := bufio.NewReader(conn)
+				for {
+					command, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					reply, ok := replies[strings.TrimSpace(command)]
+					if !ok {
+						return
+					}
+					_, _ = conn.Write([]byte(reply + "\r\n"))
+					if strings.Contains(command, "STARTTLS") || strings.Contains(command, "STLS") {
+						_ = tls.Server(conn, tlsConfig).Handshake()
+						return
+					}
+				}
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestEndpoint_EvaluateHealthWithMail(t *testing.T) {
+	// The certificate of httptest is valid for example.com
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	clientTLSConfig := &tls.Config{RootCAs: pool, ServerName: "example.com"}
+	scenarios := []struct {
+		name                   string
+		scheme                 string
+		greeting               string
+		replies                map[string]string
+		startTLS               bool
+		expectedStatus         int
+		expectedCertificate    bool
+		expectedNumberOfErrors int
+	}{
+		{
+			name:           "smtp",
+			scheme:         "smtp",
+			greeting:       "220 mail.example.org ESMTP",
+			expectedStatus: 220,
+		},
+		{
+			name:           "smtp-unavailable",
+			scheme:         "smtp",
+			greeting:       "554 no service",
+			expectedStatus: 554,
+		},
+		{
+			name:                "smtp-starttls",
+			scheme:              "smtp",
+			greeting:            "220 mail.example.org ESMTP",
+			replies:             map[string]string{"EHLO localhost": "250-mail.example.org\r\n250 STARTTLS", "STARTTLS": "220 ready"},
+			startTLS:            true,
+			expectedStatus:      220,
+			expectedCertificate: true,
+		},
+		{
+			name:                   "smtp-starttls-not-supported",
+			scheme:                 "smtp",
+			greeting:               "220 mail.example.org ESMTP",
+			replies:                map[string]string{"EHLO localhost": "250 mail.example.org", "STARTTLS": "502 not implemented"},
+			startTLS:               true,
+			expectedStatus:         220,
+			expectedNumberOfErrors: 1,
+		},
+		{
+			name:                "imap-starttls",
+			scheme:              "imap",
+			greeting:            "* OK IMAP4rev1 ready",
+			replies:             map[string]string{"a1 STARTTLS": "a1 OK begin TLS"},
+			startTLS:            true,
+			expectedStatus:      MailStatusReady,
+			expectedCertificate: true,
+		},
+		{
+			name:           "imap-bye",
+			scheme:         "imap",
+			greeting:       "* BYE too many connections",
+			expectedStatus: MailStatusNotAvailable,
+		},
+		{
+			name:                "pop3-starttls",
+			scheme:              "pop3",
+			greeting:            "+OK POP3 ready",
+			replies:             map[string]string{"STLS": "+OK begin TLS"},
+			startTLS:            true,
+			expectedStatus:      MailStatusReady,
+			expectedCertificate: true,
+		},
+		{
+			name:           "pop3-error",
+			scheme:         "pop3",
+			greeting:       "-ERR maintenance",
+			expectedStatus: MailStatusNotAvailable,
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			address := startMailServer(t, scenario.greeting, scenario.replies, server.TLS)
+			endpoint := Endpoint{
+				Name:       scenario.name,
+				URL:        scenario.scheme + "://" + address,
+				Mail:       &Mail{StartTLS: scenario.startTLS, tlsConfig: clientTLSConfig},
+				Conditions: []Condition{"[CONNECTED] == true", "[STATUS] == 220"},
+			}
+			if err := endpoint.ValidateAndSetDefaults(); err != nil {
+				t.Fatal(err)
+			}
+			result := endpoint.EvaluateHealth()
+			if !result.Connected {
+				t.Errorf("expected to be connected, got errors %v", result.Errors)
+			}
+			if result.HTTPStatus != scenario.expectedStatus {
+				t.Errorf("expected status %d, got %d", scenario.expectedStatus, result.HTTPStatus)
+			}
+			if result.Success != (scenario.expectedStatus == 220) {
+				t.Errorf("expected success to be %v, got %v", scenario.expectedStatus == 220, result.Success)
+			}
+			if hasCertificate := result.CertificateExpiration > time.Hour; hasCertificate != scenario.expectedCertificate {
+				t.Errorf("expected the certificate expiration to be set: %v, got %v", scenario.expectedCertificate, result.CertificateExpiration)
+			}
+			if len(result.Errors) != scenario.expectedNumberOfErrors {
+				t.Errorf("expected %d errors, got %v", scenario.expectedNumberOfErrors, result.Errors)
+			}
+		})
+	}
+}
@@ -14,6 +14,20 @@ type Result struct {
 	// Possible values: NOERROR, FORMERR, SERVFAIL, NXDOMAIN, NOTIMP, REFUSED
 	DNSRCode string `json:"-"`
 
+	// DNSTTL is the lowest TTL of the records in the answer of a DNS query, in seconds
+	DNSTTL uint32 `json:"-"`
+
+	// DNSAuthoritative is whether the answer of a DNS query came from an authoritative server
+	DNSAuthoritative bool `json:"-"`
+
+	// DNSSEC is the DNSSEC status of the answer of a DNS query
+	//
+	// Possible values: secure, insecure, bogus
+	DNSSEC string `json:"-"`
+
+	// DNSSECExpiration is the duration before the first of the validated DNSSEC signatures expires
+	DNSSECExpiration time.Duration `json:"-"`
+
 	// Hostname extracted from Endpoint.URL
 	Hostname string `json:"hostname,omitempty"`
 
@@ -38,6 +52,20 @@ type Result struct {
 	// Timestamp when the request was sent
 	Timestamp time.Time `json:"timestamp"`
 
+	// PacketLoss is the percentage of ICMP echo requests that were left unanswered
+	PacketLoss float64 `json:"-"`
+
+	// Jitter is the mean deviation between the round-trip times of consecutive ICMP echo requests
+	Jitter time.Duration `json:"-"`
+
+	// GRPCStatus is the serving status reported by a gRPC health check
+	//
+	// Possible values: UNKNOWN, SERVING, NOT_SERVING, SERVICE_UNKNOWN
+	GRPCStatus string `json:"-"`
+
+	// Banner is the identification string sent by an SSH server
+	Banner string `json:"-"`
+
 	// CertificateExpiration is the duration before the certificate expires
 	CertificateExpiration time.Duration `json:"-"`
 
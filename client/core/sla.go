@@ -0,0 +1,32 @@
+package core
+
+import (
+	"errors"
+)
+
+// SLAMode defines how the SLA of an endpoint is computed from its checks
+type SLAMode string
+
+const (
+	// SLAModeCondition computes the SLA as the ratio of conditions met over conditions evaluated
+	SLAModeCondition SLAMode = "condition"
+
+	// SLAModeCheck computes the SLA as the ratio of successful checks, a check being down if any condition failed
+	SLAModeCheck SLAMode = "check"
+
+	// SLAModeTime computes the SLA from the downtime between a failed check and the next check
+	SLAModeTime SLAMode = "time"
+)
+
+// ErrInvalidSLAMode is the error with which Gatus will panic if an SLA mode is unknown
+var ErrInvalidSLAMode = errors.New("invalid sla mode: must be one of condition, check or time")
+
+// Validate checks if the SLAMode is valid
+func (mode SLAMode) Validate() error {
+	switch mode {
+	case SLAModeCondition, SLAModeCheck, SLAModeTime:
+		return nil
+	default:
+		return ErrInvalidSLAMode
+	}
+}
@@ -0,0 +1,25 @@
+package core
+
+import (
+	"testing"
+)
+
+func TestSLAMode_Validate(t *testing.T) {
+	scenarios := []struct {
+		mode        SLAMode
+		expectedErr error
+	}{
+		{mode: SLAModeCondition, expectedErr: nil},
+		{mode: SLAModeCheck, expectedErr: nil},
+		{mode: SLAModeTime, expectedErr: nil},
+		{mode: "uptime", expectedErr: ErrInvalidSLAMode},
+		{mode: "", expectedErr: ErrInvalidSLAMode},
+	}
+	for _, scenario := range scenarios {
+		t.Run(string(scenario.mode), func(t *testing.T) {
+			if err := scenario.mode.Validate(); err != scenario.expectedErr {
+				t.Errorf("expected err %v, got %v", scenario.expectedErr, err)
+			}
+		})
+	}
+}
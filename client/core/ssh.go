@@ -0,0 +1,118 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/serverless-aliyun/func-status/client/util"
+	"golang.org/x/crypto/ssh"
+)
+
+var (
+	// ErrSSHWithNoUsername is the error with which Gatus will panic if an ssh endpoint has a private key but no username
+	ErrSSHWithNoUsername = errors.New("ssh endpoints with a private key must have a username")
+
+	// ErrSSHWithInvalidPrivateKey is the error with which Gatus will panic if an ssh endpoint has a private key that cannot be parsed
+	ErrSSHWithInvalidPrivateKey = errors.New("invalid ssh private key")
+)
+
+// SSH is the configuration for a Endpoint of type SSH
+type SSH struct {
+	// Username used to authenticate. If empty, only the handshake is performed.
+	Username string `yaml:"username,omitempty"`
+
+	// PrivateKey is the PEM encoded private key used to authenticate
+	PrivateKey string `yaml:"private-key,omitempty"`
+
+	signer ssh.Signer
+}
+
+func (s *SSH) validateAndSetDefault() error {
+	if len(s.PrivateKey) == 0 {
+		return nil
+	}
+	if len(s.Username) == 0 {
+		return ErrSSHWithNoUsername
+	}
+	signer, err := ssh.ParsePrivateKey([]byte(s.PrivateKey))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrSSHWithInvalidPrivateKey, err)
+	}
+	s.signer = signer
+	return nil
+}
+
+// check performs an SSH handshake with the server at the given address and, if a private key is configured,
+// authenticates with it. The endpoint is only considered connected if the authentication succeeded as well.
+//
+// The host key of the server is not verified, since the purpose is to know whether the server is reachable.
+func (s *SSH) check(address string, result *Result) {
+	tcpConn, err := net.DialTimeout("tcp", address, util.ConnectionTimeout)
+	if err != nil {
+		result.AddError(err.Error())
+		return
+	}
+	conn := &sshBannerConn{Conn: tcpConn}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(util.ConnectionTimeout))
+	handshaked := false
+	config := &ssh.ClientConfig{
+		User: s.Username,
+		HostKeyCallback: func(string, net.Addr, ssh.PublicKey) error {
+			// The host key is only presented once the key exchange succeeded
+			handshaked = true
+			return nil
+		},
+	}
+	if s.signer != nil {
+		config.Auth = []ssh.AuthMethod{ssh.PublicKeys(s.signer)}
+	}
+	clientConn, channels, requests, err := ssh.NewClientConn(conn, address, config)
+	result.Banner = conn.banner()
+	// Without a private key, the authentication is expected to fail once the handshake is done
+	if err != nil && (!handshaked || s.signer != nil) {
+		result.AddError(err.Error())
+		return
+	}
+	result.Connected = true
+	if err == nil {
+		_ = ssh.NewClient(clientConn, channels, requests).Close()
+	}
+}
+
+// sshBannerConn records the identification string sent by the server before the key exchange
+type sshBannerConn struct {
+	net.Conn
+	line []byte
+	done bool
+}
+
+func (c *sshBannerConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	for _, b := range p[:n] {
+		if c.done {
+			break
+		}
+		if b != '\n' {
+			c.line = append(c.line, b)
+			continue
+		}
+		// Servers may send other lines before the identification string, which always starts with SSH-
+		if bytes.HasPrefix(c.line, []byte("SSH-")) {
+			c.done = true
+		} else {
+			c.line = c.line[:0]
+		}
+	}
+	return n, err
+}
+
+func (c *sshBannerConn) banner() string {
+	if !c.done {
+		return ""
+	}
+	return string(bytes.TrimSuffix(c.line, []byte("\r")))
+}
@@ -0,0 +1,134 @@
+package core
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"net"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// startSSHServer starts a server that sends a banner line before its identification string and only accepts the
+// given public key
+func startSSHServer(t *testing.T, authorizedKey ssh.PublicKey) string {
+	_, hostKey, _ := ed25519.GenerateKey(rand.Reader)
+	hostSigner, err := ssh.NewSignerFromKey(hostKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &ssh.ServerConfig{
+		ServerVersion: "SSH-2.0-OpenSSH_9.6",
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if bytes.Equal(key.Marshal(), authorizedKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, errors.New("unauthorized")
+		},
+	}
+	config.AddHostKey(hostSigner)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = conn.Write([]byte("Authorized access only\r\n"))
+				if serverConn, _, _, err := ssh.NewServerConn(conn, config); err == nil {
+					serverConn.Close()
+				}
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func generateSSHKey(t *testing.T) (ssh.PublicKey, string) {
+	publicKey, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+	block, err := ssh.MarshalPrivateKey(privateKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshPublicKey, _ := ssh.NewPublicKey(publicKey)
+	return sshPublicKey, string(pem.EncodeToMemory(block))
+}
+
+func TestEndpoint_EvaluateHealthWithSSH(t *testing.T) {
+	authorizedKey, authorizedPrivateKey := generateSSHKey(t)
+	_, unauthorizedPrivateKey := generateSSHKey(t)
+	address := startSSHServer(t, authorizedKey)
+	scenarios := []struct {
+		name              string
+		ssh               *SSH
+		expectedConnected bool
+		expectedSuccess   bool
+	}{
+		{
+			name:              "handshake-only",
+			ssh:               nil,
+			expectedConnected: true,
+			expectedSuccess:   true,
+		},
+		{
+			name:              "authorized-key",
+			ssh:               &SSH{Username: "monitor", PrivateKey: authorizedPrivateKey},
+			expectedConnected: true,
+			expectedSuccess:   true,
+		},
+		{
+			name:              "unauthorized-key",
+			ssh:               &SSH{Username: "monitor", PrivateKey: unauthorizedPrivateKey},
+			expectedConnected: false,
+			expectedSuccess:   false,
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			endpoint := Endpoint{Name: "ssh", URL: "ssh://" + address, SSH: scenario.ssh, Conditions: []Condition{"[CONNECTED] == true", "[BANNER] == pat(*OpenSSH*)"}}
+			if err := endpoint.ValidateAndSetDefaults(); err != nil {
+				t.Fatal(err)
+			}
+			result := endpoint.EvaluateHealth()
+			if result.Connected != scenario.expectedConnected {
+				t.Errorf("expected connected to be %v, got %v with errors %v", scenario.expectedConnected, result.Connected, result.Errors)
+			}
+			if result.Success != scenario.expectedSuccess {
+				t.Errorf("expected success to be %v, got %v with errors %v", scenario.expectedSuccess, result.Success, result.Errors)
+			}
+			if result.Banner != "SSH-2.0-OpenSSH_9.6" {
+				t.Errorf("expected banner SSH-2.0-OpenSSH_9.6, got %q", result.Banner)
+			}
+		})
+	}
+}
+
+func TestSSH_validateAndSetDefault(t *testing.T) {
+	_, privateKey := generateSSHKey(t)
+	scenarios := []struct {
+		name          string
+		ssh           SSH
+		expectedError error
+	}{
+		{name: "handshake-only", ssh: SSH{}, expectedError: nil},
+		{name: "private-key", ssh: SSH{Username: "monitor", PrivateKey: privateKey}, expectedError: nil},
+		{name: "no-username", ssh: SSH{PrivateKey: privateKey}, expectedError: ErrSSHWithNoUsername},
+		{name: "invalid-private-key", ssh: SSH{Username: "monitor", PrivateKey: "not a key"}, expectedError: ErrSSHWithInvalidPrivateKey},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			if err := scenario.ssh.validateAndSetDefault(); !errors.Is(err, scenario.expectedError) {
+				t.Errorf("expected error %v, got %v", scenario.expectedError, err)
+			}
+		})
+	}
+}
@@ -1,8 +1,10 @@
 package jsonpath
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 )
@@ -17,9 +19,62 @@ func Eval(path string, b []byte) (string, int, error) {
 	if err := json.Unmarshal(b, &object); err != nil {
 		return "", 0, err
 	}
+	if hasImpreciseInteger(object) {
+		// Decode the numbers again, this time without converting them to float64 first
+		decoder := json.NewDecoder(bytes.NewReader(b))
+		decoder.UseNumber()
+		if err := decoder.Decode(&object); err != nil {
+			return "", 0, err
+		}
+		object = normalizeNumbers(object)
+	}
 	return walk(path, object)
 }
 
+// hasImpreciseInteger checks whether a decoded JSON value has an integer too large to be represented exactly by a
+// float64
+func hasImpreciseInteger(value interface{}) bool {
+	switch v := value.(type) {
+	case float64:
+		return math.Abs(v) >= 1<<53 && v == math.Trunc(v)
+	case map[string]interface{}:
+		for _, element := range v {
+			if hasImpreciseInteger(element) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, element := range v {
+			if hasImpreciseInteger(element) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// normalizeNumbers converts the numbers of a JSON value decoded with json.Decoder.UseNumber into int64 if they are
+// integers that fit, and into float64 otherwise, so that large integers such as IDs and timestamps don't lose precision
+func normalizeNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if number, err := v.Int64(); err == nil {
+			return number
+		}
+		number, _ := v.Float64()
+		return number
+	case map[string]interface{}:
+		for key, element := range v {
+			v[key] = normalizeNumbers(element)
+		}
+	case []interface{}:
+		for i, element := range v {
+			v[i] = normalizeNumbers(element)
+		}
+	}
+	return value
+}
+
 // walk traverses the object and returns the value as a string as well as its length
 func walk(path string, object interface{}) (string, int, error) {
 	var keys []string
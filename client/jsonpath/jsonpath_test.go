@@ -54,6 +54,30 @@ func TestEval(t *testing.T) {
 			ExpectedOutputLength: 1,
 			ExpectedError:        false,
 		},
+		{
+			Name:                 "integer-beyond-float-precision",
+			Path:                 "id",
+			Data:                 `{"id": 9007199254740993}`,
+			ExpectedOutput:       "9007199254740993",
+			ExpectedOutputLength: 16,
+			ExpectedError:        false,
+		},
+		{
+			Name:                 "float",
+			Path:                 "values[1]",
+			Data:                 `{"values": [1.0, 0.25, 1e3]}`,
+			ExpectedOutput:       "0.25",
+			ExpectedOutputLength: 4,
+			ExpectedError:        false,
+		},
+		{
+			Name:                 "array-of-numbers",
+			Path:                 "values",
+			Data:                 `{"values": [1.0, 0.25, 1e3]}`,
+			ExpectedOutput:       "[1 0.25 1000]",
+			ExpectedOutputLength: 3,
+			ExpectedError:        false,
+		},
 		{
 			Name:                 "array-of-values",
 			Path:                 "ids[0]",
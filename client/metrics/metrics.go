@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/serverless-aliyun/func-status/client/core"
+)
+
+const namespace = "func_status"
+
+var (
+	initializeOnce sync.Once
+
+	resultTotal                        *prometheus.CounterVec
+	resultSuccess                      *prometheus.GaugeVec
+	resultDurationSeconds              *prometheus.GaugeVec
+	resultHTTPStatus                   *prometheus.GaugeVec
+	resultCertificateExpirationSeconds *prometheus.GaugeVec
+	resultErrorsTotal                  *prometheus.CounterVec
+	resultConditionSuccess             *prometheus.GaugeVec
+)
+
+func initializePrometheusMetrics() {
+	resultTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "results_total",
+		Help:      "Number of results per endpoint",
+	}, []string{"key", "type", "success"})
+	resultSuccess = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "results_success",
+		Help:      "Whether the latest result of the endpoint was successful (1) or not (0)",
+	}, []string{"key", "type"})
+	resultDurationSeconds = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "results_duration_seconds",
+		Help:      "Duration of the latest request to the endpoint, in seconds",
+	}, []string{"key", "type"})
+	resultHTTPStatus = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "results_http_status",
+		Help:      "HTTP status of the latest result of the endpoint",
+	}, []string{"key", "type"})
+	resultCertificateExpirationSeconds = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "results_certificate_expiration_seconds",
+		Help:      "Number of seconds until the certificate of the endpoint expires",
+	}, []string{"key", "type"})
+	resultErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "results_errors_total",
+		Help:      "Number of errors encountered while evaluating the endpoint",
+	}, []string{"key", "type"})
+	resultConditionSuccess = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "results_condition_success",
+		Help:      "Whether each condition of the latest result of the endpoint was met (1) or not (0)",
+	}, []string{"key", "type", "condition"})
+}
+
+// PublishMetricsForEndpoint publishes the metrics for the given endpoint and its result.
+// These metrics will be exposed at /metrics.
+func PublishMetricsForEndpoint(endpoint *core.Endpoint, result *core.Result) {
+	initializeOnce.Do(initializePrometheusMetrics)
+	key, endpointType := endpoint.Key(), string(endpoint.Type())
+	resultTotal.WithLabelValues(key, endpointType, strconv.FormatBool(result.Success)).Inc()
+	resultSuccess.WithLabelValues(key, endpointType).Set(boolToFloat64(result.Success))
+	resultDurationSeconds.WithLabelValues(key, endpointType).Set(result.Duration.Seconds())
+	// The previous status must not be exported anymore if the latest request didn't get one, e.g. because it couldn't connect
+	if result.HTTPStatus != 0 {
+		resultHTTPStatus.WithLabelValues(key, endpointType).Set(float64(result.HTTPStatus))
+	} else {
+		resultHTTPStatus.DeleteLabelValues(key, endpointType)
+	}
+	// The certificate may have disappeared since the previous result, in which case its expiration must not be exported anymore
+	if result.CertificateExpiration != 0 {
+		resultCertificateExpirationSeconds.WithLabelValues(key, endpointType).Set(result.CertificateExpiration.Seconds())
+	} else {
+		resultCertificateExpirationSeconds.DeleteLabelValues(key, endpointType)
+	}
+	resultErrorsTotal.WithLabelValues(key, endpointType).Add(float64(len(result.Errors)))
+	for i, conditionResult := range result.ConditionResults {
+		// Failed conditions are displayed with their resolved values, which would create a new series on every
+		// failure, so the configured condition is used as label whenever possible.
+		condition := conditionResult.Condition
+		if len(endpoint.Conditions) == len(result.ConditionResults) {
+			condition = string(endpoint.Conditions[i])
+		}
+		resultConditionSuccess.WithLabelValues(key, endpointType, condition).Set(boolToFloat64(conditionResult.Success))
+	}
+}
+
+func boolToFloat64(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
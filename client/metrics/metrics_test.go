@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/serverless-aliyun/func-status/client/core"
+)
+
+func TestPublishMetricsForEndpoint(t *testing.T) {
+	endpoint := &core.Endpoint{
+		Name:       "website",
+		URL:        "https://example.org/health",
+		Conditions: []core.Condition{"[STATUS] == 200", "[CERTIFICATE_EXPIRATION] > 48h"},
+	}
+	PublishMetricsForEndpoint(endpoint, &core.Result{
+		HTTPStatus:            200,
+		Success:               true,
+		Duration:              250 * time.Millisecond,
+		CertificateExpiration: 72 * time.Hour,
+		ConditionResults: []*core.ConditionResult{
+			{Condition: "[STATUS] == 200", Success: true},
+			{Condition: "[CERTIFICATE_EXPIRATION] > 48h", Success: true},
+		},
+	})
+	if expiration := testutil.ToFloat64(resultCertificateExpirationSeconds.WithLabelValues("website", "HTTP")); expiration != 72*3600 {
+		t.Errorf("expected the certificate expiration to be %v, got %v", 72*3600, expiration)
+	}
+	PublishMetricsForEndpoint(endpoint, &core.Result{
+		HTTPStatus: 500,
+		Success:    false,
+		Duration:   time.Second,
+		Errors:     []string{"error 1", "error 2"},
+		ConditionResults: []*core.ConditionResult{
+			{Condition: "[STATUS] (500) == 200", Success: false},
+			{Condition: "[CERTIFICATE_EXPIRATION] > 48h", Success: true},
+		},
+	})
+	if n := testutil.CollectAndCount(resultCertificateExpirationSeconds); n != 0 {
+		t.Errorf("expected the certificate expiration to be deleted when the result has no certificate, got %d series", n)
+	}
+	scenarios := []struct {
+		name     string
+		actual   float64
+		expected float64
+	}{
+		{name: "results_total_success", actual: testutil.ToFloat64(resultTotal.WithLabelValues("website", "HTTP", "true")), expected: 1},
+		{name: "results_total_failure", actual: testutil.ToFloat64(resultTotal.WithLabelValues("website", "HTTP", "false")), expected: 1},
+		{name: "results_success", actual: testutil.ToFloat64(resultSuccess.WithLabelValues("website", "HTTP")), expected: 0},
+		{name: "results_duration_seconds", actual: testutil.ToFloat64(resultDurationSeconds.WithLabelValues("website", "HTTP")), expected: 1},
+		{name: "results_http_status", actual: testutil.ToFloat64(resultHTTPStatus.WithLabelValues("website", "HTTP")), expected: 500},
+		{name: "results_errors_total", actual: testutil.ToFloat64(resultErrorsTotal.WithLabelValues("website", "HTTP")), expected: 2},
+		{name: "results_condition_success_status", actual: testutil.ToFloat64(resultConditionSuccess.WithLabelValues("website", "HTTP", "[STATUS] == 200")), expected: 0},
+		{name: "results_condition_success_certificate", actual: testutil.ToFloat64(resultConditionSuccess.WithLabelValues("website", "HTTP", "[CERTIFICATE_EXPIRATION] > 48h")), expected: 1},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			if scenario.actual != scenario.expected {
+				t.Errorf("expected %v, got %v", scenario.expected, scenario.actual)
+			}
+		})
+	}
+	if n := testutil.CollectAndCount(resultConditionSuccess); n != 2 {
+		t.Errorf("expected the failed condition to reuse the configured condition as label, got %d series", n)
+	}
+	PublishMetricsForEndpoint(endpoint, &core.Result{
+		Success: false,
+		Errors:  []string{"connection refused"},
+		ConditionResults: []*core.ConditionResult{
+			{Condition: "[STATUS] (0) == 200", Success: false},
+			{Condition: "[CERTIFICATE_EXPIRATION] (0) > 48h", Success: false},
+		},
+	})
+	if n := testutil.CollectAndCount(resultHTTPStatus); n != 0 {
+		t.Errorf("expected the HTTP status to be deleted when the result has no status, got %d series", n)
+	}
+}
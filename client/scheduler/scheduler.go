@@ -0,0 +1,222 @@
+package scheduler
+
+import (
+	"math/rand"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/serverless-aliyun/func-status/client/core"
+)
+
+const (
+	// DefaultWorkers is the default number of endpoints evaluated at the same time
+	DefaultWorkers = 10
+
+	// DefaultPerHostLimit is the default number of endpoints sharing a host evaluated at the same time
+	DefaultPerHostLimit = 2
+
+	// intervalToleranceDivisor is the fraction of an endpoint's interval by which a run may be early and still
+	// evaluate the endpoint, so that triggers arriving slightly early don't skip every other interval
+	intervalToleranceDivisor = 20
+)
+
+// Config is the configuration of the check runner
+type Config struct {
+	// Workers is the maximum number of endpoints evaluated at the same time
+	Workers int `yaml:"workers,omitempty"`
+
+	// PerHostLimit is the maximum number of endpoints sharing the same host evaluated at the same time
+	PerHostLimit int `yaml:"perHostLimit,omitempty"`
+
+	// Jitter is the upper bound of the random delay applied before each evaluation
+	Jitter time.Duration `yaml:"jitter,omitempty"`
+
+	// Tick is the interval at which due endpoints are evaluated in-process.
+	// If not set, endpoints are only evaluated when a check is triggered externally.
+	Tick time.Duration `yaml:"tick,omitempty"`
+}
+
+// ValidateAndSetDefaults sets the default value of args that have one
+func (c *Config) ValidateAndSetDefaults() {
+	if c.Workers <= 0 {
+		c.Workers = DefaultWorkers
+	}
+	if c.PerHostLimit <= 0 {
+		c.PerHostLimit = DefaultPerHostLimit
+	}
+	if c.Jitter < 0 {
+		c.Jitter = 0
+	}
+	if c.Tick < 0 {
+		c.Tick = 0
+	}
+}
+
+// Handler is called with the result of each evaluated endpoint.
+//
+// Note that it is called concurrently from the workers of the Scheduler.
+type Handler func(endpoint *core.Endpoint, result *core.Result)
+
+// Report summarizes a run of the Scheduler
+type Report struct {
+	// Evaluated is the number of endpoints that were evaluated
+	Evaluated int `json:"evaluated"`
+
+	// Failed is the number of endpoints whose result was not successful
+	Failed int `json:"failed"`
+
+	// Skipped is the number of enabled endpoints that were not due yet or were still being evaluated by another run
+	Skipped int `json:"skipped"`
+
+	// Duration is the time the whole run took
+	Duration time.Duration `json:"duration"`
+}
+
+// Scheduler evaluates endpoints with a bounded pool of workers
+type Scheduler struct {
+	cfg *Config
+
+	mutex    sync.Mutex
+	hosts    map[string]chan struct{}
+	lastRun  map[string]time.Time
+	inFlight map[string]bool
+}
+
+// New creates a Scheduler from the given configuration
+func New(cfg *Config) *Scheduler {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	cfg.ValidateAndSetDefaults()
+	return &Scheduler{
+		cfg:      cfg,
+		hosts:    make(map[string]chan struct{}),
+		lastRun:  make(map[string]time.Time),
+		inFlight: make(map[string]bool),
+	}
+}
+
+// Start evaluates the endpoints that are due on every tick until stop is closed.
+//
+// It returns immediately if no tick is configured.
+func (s *Scheduler) Start(endpoints []*core.Endpoint, handler Handler, stop <-chan struct{}) {
+	if s.cfg.Tick == 0 {
+		return
+	}
+	ticker := time.NewTicker(s.cfg.Tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.Run(endpoints, handler)
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Run evaluates the health of every enabled endpoint that is due and returns once all of them have a result
+func (s *Scheduler) Run(endpoints []*core.Endpoint, handler Handler) *Report {
+	startTime := time.Now()
+	jobs := make(chan *core.Endpoint)
+	var (
+		wg     sync.WaitGroup
+		mutex  sync.Mutex
+		report = &Report{}
+	)
+	for i := 0; i < s.cfg.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for endpoint := range jobs {
+				result := s.evaluate(endpoint)
+				s.release(endpoint)
+				if handler != nil {
+					handler(endpoint, result)
+				}
+				mutex.Lock()
+				report.Evaluated++
+				if !result.Success {
+					report.Failed++
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+	for _, endpoint := range endpoints {
+		if !endpoint.IsEnabled() {
+			continue
+		}
+		if !s.markIfDue(endpoint, startTime) {
+			report.Skipped++
+			continue
+		}
+		jobs <- endpoint
+	}
+	close(jobs)
+	wg.Wait()
+	report.Duration = time.Since(startTime)
+	return report
+}
+
+// markIfDue returns whether the endpoint is due at the given time and, if it is, records it as the endpoint's last run
+// and marks it as in flight until release is called.
+//
+// The last run is recorded before the evaluation so that overlapping runs don't evaluate an endpoint with an interval twice,
+// and an endpoint that is still being evaluated, e.g. by the ticker while a check is triggered externally, is never due.
+func (s *Scheduler) markIfDue(endpoint *core.Endpoint, now time.Time) bool {
+	key := endpoint.Key()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.inFlight[key] {
+		return false
+	}
+	if lastRun, exists := s.lastRun[key]; exists && endpoint.Interval > 0 {
+		if now.Sub(lastRun) < endpoint.Interval-endpoint.Interval/intervalToleranceDivisor {
+			return false
+		}
+	}
+	s.lastRun[key] = now
+	s.inFlight[key] = true
+	return true
+}
+
+// release marks the endpoint as no longer being evaluated
+func (s *Scheduler) release(endpoint *core.Endpoint) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.inFlight, endpoint.Key())
+}
+
+// evaluate waits for the jitter and a free slot on the endpoint's host before evaluating its health
+func (s *Scheduler) evaluate(endpoint *core.Endpoint) *core.Result {
+	if s.cfg.Jitter > 0 {
+		time.Sleep(time.Duration(rand.Int63n(int64(s.cfg.Jitter))))
+	}
+	slot := s.hostSlot(endpoint)
+	slot <- struct{}{}
+	defer func() { <-slot }()
+	return endpoint.EvaluateHealth()
+}
+
+// hostSlot returns the semaphore limiting the concurrent evaluations of the endpoint's host
+func (s *Scheduler) hostSlot(endpoint *core.Endpoint) chan struct{} {
+	host := hostOf(endpoint)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	slot, exists := s.hosts[host]
+	if !exists {
+		slot = make(chan struct{}, s.cfg.PerHostLimit)
+		s.hosts[host] = slot
+	}
+	return slot
+}
+
+// hostOf extracts the host from the endpoint's URL, falling back to the URL itself (e.g. DNS endpoints)
+func hostOf(endpoint *core.Endpoint) string {
+	if urlObject, err := url.Parse(endpoint.URL); err == nil && len(urlObject.Hostname()) > 0 {
+		return urlObject.Hostname()
+	}
+	return endpoint.URL
+}
@@ -0,0 +1,202 @@
+package scheduler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/serverless-aliyun/func-status/client/core"
+)
+
+func TestConfig_ValidateAndSetDefaults(t *testing.T) {
+	cfg := &Config{Jitter: -time.Second}
+	cfg.ValidateAndSetDefaults()
+	if cfg.Workers != DefaultWorkers {
+		t.Errorf("expected workers to be %d, got %d", DefaultWorkers, cfg.Workers)
+	}
+	if cfg.PerHostLimit != DefaultPerHostLimit {
+		t.Errorf("expected per host limit to be %d, got %d", DefaultPerHostLimit, cfg.PerHostLimit)
+	}
+	if cfg.Jitter != 0 {
+		t.Errorf("expected jitter to be 0, got %s", cfg.Jitter)
+	}
+}
+
+func TestScheduler_Run(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		for {
+			observed := atomic.LoadInt32(&maxInFlight)
+			if current <= observed || atomic.CompareAndSwapInt32(&maxInFlight, observed, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+	disabled := false
+	var endpoints []*core.Endpoint
+	for i := 0; i < 10; i++ {
+		endpoints = append(endpoints, &core.Endpoint{
+			Name:       "endpoint-" + strconv.Itoa(i),
+			URL:        server.URL + "/ok",
+			Conditions: []core.Condition{"[STATUS] == 200"},
+		})
+	}
+	endpoints = append(endpoints, &core.Endpoint{
+		Name:       "failing",
+		URL:        server.URL + "/fail",
+		Conditions: []core.Condition{"[STATUS] == 200"},
+	})
+	endpoints = append(endpoints, &core.Endpoint{
+		Name:       "disabled",
+		Enabled:    &disabled,
+		URL:        server.URL + "/ok",
+		Conditions: []core.Condition{"[STATUS] == 200"},
+	})
+	var mutex sync.Mutex
+	evaluated := make(map[string]bool)
+	s := New(&Config{Workers: 5, PerHostLimit: 3})
+	report := s.Run(endpoints, func(endpoint *core.Endpoint, result *core.Result) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		evaluated[endpoint.Name] = result.Success
+	})
+	if report.Evaluated != 11 {
+		t.Errorf("expected 11 endpoints to be evaluated, got %d", report.Evaluated)
+	}
+	if report.Failed != 1 {
+		t.Errorf("expected 1 endpoint to fail, got %d", report.Failed)
+	}
+	if report.Duration <= 0 {
+		t.Error("expected the duration of the run to be reported")
+	}
+	if _, exists := evaluated["disabled"]; exists {
+		t.Error("disabled endpoint shouldn't have been evaluated")
+	}
+	if success, exists := evaluated["failing"]; !exists || success {
+		t.Error("failing endpoint should've been evaluated and failed")
+	}
+	if maxInFlight > 3 {
+		t.Errorf("expected at most 3 concurrent requests to the same host, got %d", maxInFlight)
+	}
+}
+
+func TestScheduler_RunWithInterval(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	endpoints := []*core.Endpoint{
+		{Name: "every-check", URL: server.URL, Conditions: []core.Condition{"[STATUS] == 200"}},
+		{Name: "hourly", URL: server.URL, Interval: time.Hour, Conditions: []core.Condition{"[STATUS] == 200"}},
+	}
+	s := New(nil)
+	if report := s.Run(endpoints, nil); report.Evaluated != 2 || report.Skipped != 0 {
+		t.Errorf("expected both endpoints to be evaluated on the first run, got %+v", report)
+	}
+	if report := s.Run(endpoints, nil); report.Evaluated != 1 || report.Skipped != 1 {
+		t.Errorf("expected the hourly endpoint to be skipped on the second run, got %+v", report)
+	}
+	s.lastRun[endpoints[1].Key()] = time.Now().Add(-time.Hour)
+	if report := s.Run(endpoints, nil); report.Evaluated != 2 || report.Skipped != 0 {
+		t.Errorf("expected the hourly endpoint to be due again after an hour, got %+v", report)
+	}
+	s.lastRun[endpoints[1].Key()] = time.Now().Add(-time.Hour + time.Second)
+	if report := s.Run(endpoints, nil); report.Evaluated != 2 || report.Skipped != 0 {
+		t.Errorf("expected the hourly endpoint to be due when the run is slightly early, got %+v", report)
+	}
+}
+
+func TestScheduler_RunSkipsEndpointsInFlight(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	endpoints := []*core.Endpoint{{Name: "slow", URL: server.URL, Conditions: []core.Condition{"[STATUS] == 200"}}}
+	s := New(nil)
+	done := make(chan *Report)
+	go func() {
+		done <- s.Run(endpoints, nil)
+	}()
+	for {
+		s.mutex.Lock()
+		inFlight := s.inFlight[endpoints[0].Key()]
+		s.mutex.Unlock()
+		if inFlight {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if report := s.Run(endpoints, nil); report.Evaluated != 0 || report.Skipped != 1 {
+		t.Errorf("expected the endpoint being evaluated to be skipped, got %+v", report)
+	}
+	close(release)
+	if report := <-done; report.Evaluated != 1 {
+		t.Errorf("expected the first run to evaluate the endpoint, got %+v", report)
+	}
+	if report := s.Run(endpoints, nil); report.Evaluated != 1 {
+		t.Errorf("expected the endpoint to be evaluated once it is no longer in flight, got %+v", report)
+	}
+}
+
+func TestScheduler_Start(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	endpoints := []*core.Endpoint{{Name: "ticking", URL: server.URL, Conditions: []core.Condition{"[STATUS] == 200"}}}
+	var evaluations int32
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	s := New(&Config{Tick: 10 * time.Millisecond})
+	go func() {
+		s.Start(endpoints, func(endpoint *core.Endpoint, result *core.Result) {
+			atomic.AddInt32(&evaluations, 1)
+		}, stop)
+		close(done)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	close(stop)
+	<-done
+	if n := atomic.LoadInt32(&evaluations); n < 2 {
+		t.Errorf("expected the endpoint to be evaluated on several ticks, got %d evaluations", n)
+	}
+}
+
+func TestScheduler_StartWithoutTick(t *testing.T) {
+	done := make(chan struct{})
+	go func() {
+		New(nil).Start(nil, nil, nil)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("Start should've returned immediately because no tick is configured")
+	}
+}
+
+func TestHostOf(t *testing.T) {
+	scenarios := []struct {
+		url          string
+		expectedHost string
+	}{
+		{url: "https://example.org/health", expectedHost: "example.org"},
+		{url: "http://127.0.0.1:8080", expectedHost: "127.0.0.1"},
+		{url: "8.8.8.8", expectedHost: "8.8.8.8"},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.url, func(t *testing.T) {
+			if host := hostOf(&core.Endpoint{URL: scenario.url}); host != scenario.expectedHost {
+				t.Errorf("expected host %s, got %s", scenario.expectedHost, host)
+			}
+		})
+	}
+}
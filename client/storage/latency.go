@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"math"
+	"sort"
+)
+
+// ResponseTime is the distribution of the response times of a day, in milliseconds
+type ResponseTime struct {
+	Min int64 `gorm:"column:min" json:"min"`
+	Avg int64 `gorm:"column:avg" json:"avg"`
+	P50 int64 `gorm:"column:p50" json:"p50"`
+	P95 int64 `gorm:"column:p95" json:"p95"`
+	P99 int64 `gorm:"column:p99" json:"p99"`
+	Max int64 `gorm:"column:max" json:"max"`
+}
+
+// DayResponseTime is the ResponseTime of an endpoint for a given day
+type DayResponseTime struct {
+	// Day of check health
+	Day string `json:"day"`
+
+	ResponseTime
+}
+
+// aggregateResponseTimes computes the distribution of the response times of the checks
+func aggregateResponseTimes(checks []Check) ResponseTime {
+	if len(checks) == 0 {
+		return ResponseTime{}
+	}
+	durations := make([]int64, len(checks))
+	var total int64
+	for i, c := range checks {
+		durations[i] = c.Duration
+		total += c.Duration
+	}
+	sort.Slice(durations, func(i, j int) bool {
+		return durations[i] < durations[j]
+	})
+	return ResponseTime{
+		Min: durations[0],
+		Avg: int64(math.Round(float64(total) / float64(len(durations)))),
+		P50: percentile(durations, 50),
+		P95: percentile(durations, 95),
+		P99: percentile(durations, 99),
+		Max: durations[len(durations)-1],
+	}
+}
+
+// percentile returns the p-th percentile of the sorted durations using the nearest-rank method
+func percentile(sortedDurations []int64, p float64) int64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sortedDurations))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sortedDurations[rank-1]
+}
+
+// toDayResponseTimes extracts the response times from the daily results
+func toDayResponseTimes(results []Result) []DayResponseTime {
+	responseTimes := make([]DayResponseTime, 0, len(results))
+	for _, r := range results {
+		responseTimes = append(responseTimes, DayResponseTime{Day: r.Day, ResponseTime: r.ResponseTime})
+	}
+	return responseTimes
+}
@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"testing"
+)
+
+func TestAggregateResponseTimes(t *testing.T) {
+	scenarios := []struct {
+		name      string
+		durations []int64
+		expected  ResponseTime
+	}{
+		{
+			name:      "no-checks",
+			durations: nil,
+			expected:  ResponseTime{},
+		},
+		{
+			name:      "single-check",
+			durations: []int64{42},
+			expected:  ResponseTime{Min: 42, Avg: 42, P50: 42, P95: 42, P99: 42, Max: 42},
+		},
+		{
+			name:      "unordered-checks",
+			durations: []int64{300, 100, 200, 400},
+			expected:  ResponseTime{Min: 100, Avg: 250, P50: 200, P95: 400, P99: 400, Max: 400},
+		},
+		{
+			name:      "hundred-checks-with-outlier",
+			durations: append(repeat(10, 98), 500, 5000),
+			expected:  ResponseTime{Min: 10, Avg: 65, P50: 10, P95: 10, P99: 500, Max: 5000},
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			var checks []Check
+			for _, duration := range scenario.durations {
+				checks = append(checks, Check{Duration: duration})
+			}
+			if responseTime := aggregateResponseTimes(checks); responseTime != scenario.expected {
+				t.Errorf("expected %+v, got %+v", scenario.expected, responseTime)
+			}
+		})
+	}
+}
+
+func repeat(duration int64, n int) []int64 {
+	durations := make([]int64, n)
+	for i := range durations {
+		durations[i] = duration
+	}
+	return durations
+}
@@ -0,0 +1,236 @@
+package storage
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/serverless-aliyun/func-status/client/core"
+)
+
+// memoryStore is a Store keeping everything in memory, mostly useful for running locally and for tests.
+//
+// Note that everything is lost when the process exits.
+type memoryStore struct {
+	mutex     sync.RWMutex
+	endpoints map[string]*Endpoint
+	results   map[string]map[string]*Result
+	checks    map[string][]*Check
+	incidents []*Incident
+	lastID    uint
+}
+
+// NewMemoryStore creates an empty in-memory Store
+func NewMemoryStore() Store {
+	return &memoryStore{
+		endpoints: make(map[string]*Endpoint),
+		results:   make(map[string]map[string]*Result),
+		checks:    make(map[string][]*Check),
+	}
+}
+
+func (s *memoryStore) SaveResult(key string, result *core.Result, maxDays int, mode core.SLAMode) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	now := time.Now()
+	days, exists := s.results[key]
+	if !exists {
+		days = make(map[string]*Result)
+		s.results[key] = days
+	}
+	deleteDate := now.AddDate(0, 0, -maxDays)
+	for day, dayResult := range days {
+		if dayResult.CreatedAt.Before(deleteDate) {
+			delete(days, day)
+		}
+	}
+	check := newCheck(key, result)
+	check.ID = s.nextID()
+	checks := []*Check{}
+	for _, c := range s.checks[key] {
+		if !c.CheckedAt.Before(deleteDate) {
+			checks = append(checks, c)
+		}
+	}
+	previous := s.lastCheckBefore(key, check.CheckedAt)
+	s.checks[key] = append(checks, check)
+	s.refreshDay(key, check.CheckedAt, mode, now)
+	// The previous day of checks was last aggregated before it was over, so its last state didn't last until its end yet
+	if dayStart, _ := dayBounds(check.CheckedAt); previous != nil && previous.CheckedAt.Before(dayStart) {
+		s.refreshDay(key, previous.CheckedAt, mode, now)
+	}
+	return nil
+}
+
+// refreshDay aggregates the checks of the day of the given time into the daily result of the endpoint
+func (s *memoryStore) refreshDay(key string, t time.Time, mode core.SLAMode, now time.Time) {
+	dayStart, dayEnd := dayBounds(t)
+	var dayChecks []Check
+	for _, c := range s.checks[key] {
+		if !c.CheckedAt.Before(dayStart) && c.CheckedAt.Before(dayEnd) {
+			dayChecks = append(dayChecks, *c)
+		}
+	}
+	day := dayStart.Format("2006-01-02")
+	dayResult, exists := s.results[key][day]
+	if !exists {
+		dayResult = &Result{Key: key, Day: day, Status: StatusNoData}
+		dayResult.ID = s.nextID()
+		dayResult.CreatedAt = now
+		s.results[key][day] = dayResult
+	}
+	aggregateDay(dayResult, s.lastCheckBefore(key, dayStart), dayChecks, dayStart, aggregatedUntil(dayEnd), mode)
+	dayResult.UpdatedAt = now
+}
+
+// lastCheckBefore returns the latest check of the endpoint before the given time, or nil if there is none
+func (s *memoryStore) lastCheckBefore(key string, t time.Time) *Check {
+	var last *Check
+	for _, c := range s.checks[key] {
+		if c.CheckedAt.Before(t) && (last == nil || c.CheckedAt.After(last.CheckedAt)) {
+			last = c
+		}
+	}
+	return last
+}
+
+func (s *memoryStore) SaveEndpoint(e *core.Endpoint) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	now := time.Now()
+	endpoint, exists := s.endpoints[e.Key()]
+	if !exists {
+		endpoint = &Endpoint{}
+		endpoint.ID = s.nextID()
+		endpoint.CreatedAt = now
+		s.endpoints[e.Key()] = endpoint
+	}
+	updateEndpoint(endpoint, e, s.resultsOf(e.Key()))
+	endpoint.UpdatedAt = now
+	return nil
+}
+
+func (s *memoryStore) SaveIncident(key string, result *core.Result, maxDays int) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	deleteDate := time.Now().AddDate(0, 0, -maxDays)
+	incidents := []*Incident{}
+	for _, incident := range s.incidents {
+		if incident.Key != key || incident.ResolvedAt == nil || !incident.ResolvedAt.Before(deleteDate) {
+			incidents = append(incidents, incident)
+		}
+	}
+	s.incidents = incidents
+	var ongoing *Incident
+	for _, incident := range s.incidents {
+		if incident.Key == key && incident.ResolvedAt == nil && (ongoing == nil || incident.OpenedAt.After(ongoing.OpenedAt)) {
+			ongoing = incident
+		}
+	}
+	if ongoing != nil {
+		updateIncident(ongoing, true, key, result)
+		return nil
+	}
+	incident := &Incident{}
+	if updateIncident(incident, false, key, result) {
+		incident.ID = s.nextID()
+		incident.CreatedAt = time.Now()
+		s.incidents = append(s.incidents, incident)
+	}
+	return nil
+}
+
+func (s *memoryStore) ListEndpoints(page, pageSize int) ([]Endpoint, int64, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	endpoints := make([]Endpoint, 0, len(s.endpoints))
+	for _, endpoint := range s.endpoints {
+		endpoints = append(endpoints, *endpoint)
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].Key < endpoints[j].Key
+	})
+	return paginate(endpoints, page, pageSize), int64(len(endpoints)), nil
+}
+
+func (s *memoryStore) ListResults(key string, days int) ([]Result, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	since := sinceDay(days)
+	results := []Result{}
+	for _, r := range s.resultsOf(key) {
+		if r.Day >= since {
+			results = append(results, r)
+		}
+	}
+	return results, nil
+}
+
+func (s *memoryStore) ListResponseTimes(key string, days int) ([]DayResponseTime, error) {
+	results, err := s.ListResults(key, days)
+	return toDayResponseTimes(results), err
+}
+
+func (s *memoryStore) ListChecks(key string, page, pageSize int) ([]Check, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	checks := make([]Check, 0, len(s.checks[key]))
+	for i := len(s.checks[key]) - 1; i >= 0; i-- {
+		checks = append(checks, *s.checks[key][i])
+	}
+	sort.SliceStable(checks, func(i, j int) bool {
+		return checks[i].CheckedAt.After(checks[j].CheckedAt)
+	})
+	return paginate(checks, page, pageSize), nil
+}
+
+func (s *memoryStore) ListIncidents(key string, limit int) ([]Incident, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	incidents := []Incident{}
+	for _, incident := range s.sortedIncidents() {
+		if incident.Key == key {
+			incidents = append(incidents, incident)
+		}
+	}
+	return paginate(incidents, 1, limit), nil
+}
+
+func (s *memoryStore) ListAllIncidents(limit int) ([]Incident, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return paginate(s.sortedIncidents(), 1, limit), nil
+}
+
+func (s *memoryStore) Close() error {
+	return nil
+}
+
+// resultsOf returns a copy of the daily results of an endpoint, most recent first
+func (s *memoryStore) resultsOf(key string) []Result {
+	results := make([]Result, 0, len(s.results[key]))
+	for _, r := range s.results[key] {
+		results = append(results, *r)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Day > results[j].Day
+	})
+	return results
+}
+
+// sortedIncidents returns a copy of all incidents, most recent first
+func (s *memoryStore) sortedIncidents() []Incident {
+	incidents := make([]Incident, 0, len(s.incidents))
+	for _, incident := range s.incidents {
+		incidents = append(incidents, *incident)
+	}
+	sort.SliceStable(incidents, func(i, j int) bool {
+		return incidents[i].OpenedAt.After(incidents[j].OpenedAt)
+	})
+	return incidents
+}
+
+func (s *memoryStore) nextID() uint {
+	s.lastID++
+	return s.lastID
+}
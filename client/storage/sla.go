@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/serverless-aliyun/func-status/client/core"
+)
+
+// aggregateDay computes the aggregates, the response times and the SLA of a day from all the checks of that day.
+//
+// previous is the last check before the start of the day, if any, whose state lasts until the first check of the day.
+// The state of the last check of the day lasts until end, which is the end of the day, or now if the day isn't over.
+func aggregateDay(dayResult *Result, previous *Check, checks []Check, dayStart, end time.Time, mode core.SLAMode) {
+	sort.SliceStable(checks, func(i, j int) bool {
+		return checks[i].CheckedAt.Before(checks[j].CheckedAt)
+	})
+	dayResult.Checks, dayResult.SuccessfulChecks = 0, 0
+	dayResult.Conditions, dayResult.SuccessfulConditions = 0, 0
+	var downtime, monitored time.Duration
+	if len(checks) > 0 {
+		start := checks[0].CheckedAt
+		if previous != nil {
+			start = dayStart
+			if !previous.Success {
+				downtime += checks[0].CheckedAt.Sub(dayStart)
+			}
+		}
+		if last := checks[len(checks)-1].CheckedAt; end.Before(last) {
+			end = last
+		}
+		monitored = end.Sub(start)
+	}
+	for i, c := range checks {
+		dayResult.Checks++
+		if !c.Success {
+			// The endpoint is considered down until the next check, or until the end of the period for the last check
+			next := end
+			if i+1 < len(checks) {
+				next = checks[i+1].CheckedAt
+			}
+			downtime += next.Sub(c.CheckedAt)
+		} else {
+			dayResult.SuccessfulChecks++
+		}
+		for _, cr := range c.Conditions {
+			dayResult.Conditions++
+			if cr.Success {
+				dayResult.SuccessfulConditions++
+			}
+		}
+	}
+	dayResult.Downtime, dayResult.Monitored = downtime.Minutes(), monitored.Minutes()
+	dayResult.ResponseTime = aggregateResponseTimes(checks)
+	// 计算SLA
+	dayResult.Status, dayResult.SLA = calcDaySLA(dayResult, mode)
+}
+
+// calcDaySLA 每日状态计算: 全部成功 success (sla: 100)/全部失败 failure (sla: 0)/部分成功失败 partial
+func calcDaySLA(dayResult *Result, mode core.SLAMode) (status string, sla float64) {
+	return calcEndpointSLA([]Result{*dayResult}, mode)
+}
+
+// calcEndpointSLA 整体状态计算: 按 SLA 模式汇总所有每日结果
+//
+//   - condition: 成功 condition / condition 总数
+//   - check: 成功检查 / 检查总数, 任一 condition 失败即视为检查失败
+//   - time: (监控时长 - 故障时长) / 监控时长, 监控时长为 0 时按 check 模式计算
+func calcEndpointSLA(results []Result, mode core.SLAMode) (status string, sla float64) {
+	var checks, successfulChecks, conditions, successfulConditions int
+	var monitored, downtime float64
+	for _, r := range results {
+		checks += r.Checks
+		successfulChecks += r.SuccessfulChecks
+		conditions += r.Conditions
+		successfulConditions += r.SuccessfulConditions
+		monitored += r.Monitored
+		downtime += r.Downtime
+	}
+	switch mode {
+	case core.SLAModeTime:
+		if monitored > 0 {
+			return calcSLA(monitored-downtime, monitored)
+		}
+		return calcSLA(float64(successfulChecks), float64(checks))
+	case core.SLAModeCheck:
+		return calcSLA(float64(successfulChecks), float64(checks))
+	default:
+		return calcSLA(float64(successfulConditions), float64(conditions))
+	}
+}
+
+func calcSLA(success, total float64) (status string, sla float64) {
+	if success <= 0 {
+		status = StatusFailure
+		sla = 0
+	} else if success >= total {
+		status = StatusSuccess
+		sla = 100
+	} else {
+		status = StatusPartial
+		sla = math.Round(success * 100 / total)
+	}
+	return status, sla
+}
@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/serverless-aliyun/func-status/client/core"
+)
+
+func newTestCheck(minute int, conditions ...bool) Check {
+	check := Check{CheckedAt: time.Date(2024, 1, 1, 10, minute, 0, 0, time.UTC), Success: true}
+	for _, success := range conditions {
+		check.Conditions = append(check.Conditions, ConditionResult{Condition: "[STATUS] == 200", Success: success})
+		if !success {
+			check.Success = false
+		}
+	}
+	return check
+}
+
+func TestAggregateDay(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		mode           core.SLAMode
+		previous       *Check
+		checks         []Check
+		end            time.Time
+		expectedStatus string
+		expectedSLA    float64
+	}{
+		{
+			name:           "condition-all-successful",
+			mode:           core.SLAModeCondition,
+			checks:         []Check{newTestCheck(0, true, true), newTestCheck(10, true, true)},
+			expectedStatus: StatusSuccess,
+			expectedSLA:    100,
+		},
+		{
+			name:           "condition-one-of-five-failed",
+			mode:           core.SLAModeCondition,
+			checks:         []Check{newTestCheck(0, true, true, true, true, false)},
+			expectedStatus: StatusPartial,
+			expectedSLA:    80,
+		},
+		{
+			name:           "check-one-of-five-failed",
+			mode:           core.SLAModeCheck,
+			checks:         []Check{newTestCheck(0, true, true, true, true, false)},
+			expectedStatus: StatusFailure,
+			expectedSLA:    0,
+		},
+		{
+			name:           "check-one-of-four-failed",
+			mode:           core.SLAModeCheck,
+			checks:         []Check{newTestCheck(0, true, true), newTestCheck(1, true, false), newTestCheck(2, true, true), newTestCheck(3, true, true)},
+			expectedStatus: StatusPartial,
+			expectedSLA:    75,
+		},
+		{
+			name:           "check-all-failed",
+			mode:           core.SLAModeCheck,
+			checks:         []Check{newTestCheck(0, false), newTestCheck(1, true, false)},
+			expectedStatus: StatusFailure,
+			expectedSLA:    0,
+		},
+		{
+			name:           "time-ten-minutes-down-out-of-thirty",
+			mode:           core.SLAModeTime,
+			checks:         []Check{newTestCheck(0, true), newTestCheck(10, false), newTestCheck(20, true), newTestCheck(30, true)},
+			expectedStatus: StatusPartial,
+			expectedSLA:    67,
+		},
+		{
+			name:           "time-consecutive-failures",
+			mode:           core.SLAModeTime,
+			checks:         []Check{newTestCheck(0, true), newTestCheck(1, false), newTestCheck(2, false), newTestCheck(3, true), newTestCheck(40, true)},
+			expectedStatus: StatusPartial,
+			expectedSLA:    95,
+		},
+		{
+			name:           "time-unordered-checks",
+			mode:           core.SLAModeTime,
+			checks:         []Check{newTestCheck(30, true), newTestCheck(10, false), newTestCheck(0, true), newTestCheck(20, true)},
+			expectedStatus: StatusPartial,
+			expectedSLA:    67,
+		},
+		{
+			name:           "time-last-check-failed",
+			mode:           core.SLAModeTime,
+			checks:         []Check{newTestCheck(0, true), newTestCheck(10, false)},
+			expectedStatus: StatusPartial,
+			expectedSLA:    50,
+			end:            newTestCheck(20).CheckedAt,
+		},
+		{
+			name:           "time-last-check-failed-until-end-of-day",
+			mode:           core.SLAModeTime,
+			checks:         []Check{newTestCheck(0, true), newTestCheck(10, false)},
+			expectedStatus: StatusPartial,
+			expectedSLA:    1,
+			end:            time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:           "time-failure-carried-from-previous-day",
+			mode:           core.SLAModeTime,
+			previous:       &Check{CheckedAt: time.Date(2023, 12, 31, 23, 50, 0, 0, time.UTC), Success: false},
+			checks:         []Check{newTestCheck(0, true)},
+			expectedStatus: StatusPartial,
+			expectedSLA:    5,
+			end:            newTestCheck(30).CheckedAt,
+		},
+		{
+			name:           "time-success-carried-from-previous-day",
+			mode:           core.SLAModeTime,
+			previous:       &Check{CheckedAt: time.Date(2023, 12, 31, 23, 50, 0, 0, time.UTC), Success: true},
+			checks:         []Check{newTestCheck(0, false), newTestCheck(30, true)},
+			expectedStatus: StatusPartial,
+			expectedSLA:    95,
+		},
+		{
+			name:           "time-single-failed-check-falls-back-to-check",
+			mode:           core.SLAModeTime,
+			checks:         []Check{newTestCheck(0, false)},
+			expectedStatus: StatusFailure,
+			expectedSLA:    0,
+		},
+		{
+			name:           "no-checks",
+			mode:           core.SLAModeCheck,
+			checks:         nil,
+			expectedStatus: StatusFailure,
+			expectedSLA:    0,
+		},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			dayResult := &Result{}
+			dayStart := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			end := scenario.end
+			if end.IsZero() && len(scenario.checks) > 0 {
+				// Unless specified, the day is aggregated right after its latest check
+				end = scenario.checks[0].CheckedAt
+				for _, c := range scenario.checks {
+					if c.CheckedAt.After(end) {
+						end = c.CheckedAt
+					}
+				}
+			}
+			aggregateDay(dayResult, scenario.previous, scenario.checks, dayStart, end, scenario.mode)
+			if dayResult.Status != scenario.expectedStatus {
+				t.Errorf("expected status %s, got %s", scenario.expectedStatus, dayResult.Status)
+			}
+			if dayResult.SLA != scenario.expectedSLA {
+				t.Errorf("expected SLA %v, got %v", scenario.expectedSLA, dayResult.SLA)
+			}
+		})
+	}
+}
+
+func TestCalcEndpointSLA(t *testing.T) {
+	results := []Result{
+		{Checks: 10, SuccessfulChecks: 10, Conditions: 20, SuccessfulConditions: 20, Monitored: 600, Downtime: 0},
+		{Checks: 10, SuccessfulChecks: 5, Conditions: 20, SuccessfulConditions: 15, Monitored: 600, Downtime: 300},
+	}
+	scenarios := []struct {
+		mode           core.SLAMode
+		expectedStatus string
+		expectedSLA    float64
+	}{
+		{mode: core.SLAModeCondition, expectedStatus: StatusPartial, expectedSLA: 88},
+		{mode: core.SLAModeCheck, expectedStatus: StatusPartial, expectedSLA: 75},
+		{mode: core.SLAModeTime, expectedStatus: StatusPartial, expectedSLA: 75},
+		{mode: "", expectedStatus: StatusPartial, expectedSLA: 88},
+	}
+	for _, scenario := range scenarios {
+		t.Run(string(scenario.mode), func(t *testing.T) {
+			status, sla := calcEndpointSLA(results, scenario.mode)
+			if status != scenario.expectedStatus {
+				t.Errorf("expected status %s, got %s", scenario.expectedStatus, status)
+			}
+			if sla != scenario.expectedSLA {
+				t.Errorf("expected SLA %v, got %v", scenario.expectedSLA, sla)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/serverless-aliyun/func-status/client/core"
+	"gorm.io/gorm"
+)
+
+// sqlStore is a Store backed by a SQL database through gorm
+type sqlStore struct {
+	db *gorm.DB
+}
+
+// newSQLStore opens the database with the given dialector and migrates its schema
+func newSQLStore(dialector gorm.Dialector, maxOpenConns int) (*sqlStore, error) {
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+	if maxOpenConns > 0 {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		sqlDB.SetMaxOpenConns(maxOpenConns)
+	}
+	err = db.AutoMigrate(&Endpoint{}, &Result{}, &Check{}, &Incident{})
+	if err != nil {
+		return nil, err
+	}
+	return &sqlStore{db: db}, nil
+}
+
+func (s *sqlStore) SaveResult(key string, result *core.Result, maxDays int, mode core.SLAMode) error {
+	// 删除历史数据
+	deleteDate := time.Now().AddDate(0, 0, -maxDays)
+	if err := s.db.Where("key = ? AND checked_at < ?", key, deleteDate).Delete(&Check{}).Error; err != nil {
+		return err
+	}
+	if err := s.db.Where("key = ? AND created_at < ?", key, deleteDate).Delete(&Result{}).Error; err != nil {
+		return err
+	}
+	// 保存原始数据
+	check := newCheck(key, result)
+	previous, err := s.lastCheckBefore(key, check.CheckedAt)
+	if err != nil {
+		return err
+	}
+	if err := s.db.Create(check).Error; err != nil {
+		return err
+	}
+	// 重新计算当天数据
+	if err := s.refreshDay(key, check.CheckedAt, mode); err != nil {
+		return err
+	}
+	// 前一个检查日最后一次计算时尚未结束, 重新计算以统计其最后状态持续到当天结束的时长
+	if dayStart, _ := dayBounds(check.CheckedAt); previous != nil && previous.CheckedAt.Before(dayStart) {
+		return s.refreshDay(key, previous.CheckedAt.In(check.CheckedAt.Location()), mode)
+	}
+	return nil
+}
+
+// refreshDay aggregates the checks of the day of the given time into the daily result of the endpoint
+func (s *sqlStore) refreshDay(key string, t time.Time, mode core.SLAMode) error {
+	dayStart, dayEnd := dayBounds(t)
+	var checks []Check
+	if err := s.db.Where("key = ? AND checked_at >= ? AND checked_at < ?", key, dayStart, dayEnd).Find(&checks).Error; err != nil {
+		return err
+	}
+	previous, err := s.lastCheckBefore(key, dayStart)
+	if err != nil {
+		return err
+	}
+	day := dayStart.Format("2006-01-02")
+	dayResult := &Result{
+		Key:    key,
+		Day:    day,
+		SLA:    0,
+		Status: StatusNoData,
+	}
+	if err := s.db.Where(&Result{Key: key, Day: day}).Limit(1).Find(dayResult).Error; err != nil {
+		return err
+	}
+	aggregateDay(dayResult, previous, checks, dayStart, aggregatedUntil(dayEnd), mode)
+	return s.db.Save(dayResult).Error
+}
+
+// lastCheckBefore returns the latest check of the endpoint before the given time, or nil if there is none
+func (s *sqlStore) lastCheckBefore(key string, t time.Time) (*Check, error) {
+	var checks []Check
+	if err := s.db.Where("key = ? AND checked_at < ?", key, t).Order("checked_at DESC").Limit(1).Find(&checks).Error; err != nil {
+		return nil, err
+	}
+	if len(checks) == 0 {
+		return nil, nil
+	}
+	return &checks[0], nil
+}
+
+func (s *sqlStore) SaveEndpoint(e *core.Endpoint) error {
+	endpoint := &Endpoint{}
+	if err := s.db.Where(&Endpoint{Key: e.Key()}).Limit(1).Find(endpoint).Error; err != nil {
+		return err
+	}
+	var results []Result
+	if err := s.db.Where(&Result{Key: e.Key()}).Find(&results).Error; err != nil {
+		return err
+	}
+	updateEndpoint(endpoint, e, results)
+	return s.db.Save(endpoint).Error
+}
+
+func (s *sqlStore) SaveIncident(key string, result *core.Result, maxDays int) error {
+	deleteDate := time.Now().AddDate(0, 0, -maxDays)
+	if err := s.db.Unscoped().Where("key = ? AND resolved_at < ?", key, deleteDate).Delete(&Incident{}).Error; err != nil {
+		return err
+	}
+	incident := &Incident{}
+	tx := s.db.Where("key = ? AND resolved_at IS NULL", key).Order("opened_at DESC").Limit(1).Find(incident)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if !updateIncident(incident, tx.RowsAffected > 0, key, result) {
+		return nil
+	}
+	return s.db.Save(incident).Error
+}
+
+func (s *sqlStore) ListEndpoints(page, pageSize int) ([]Endpoint, int64, error) {
+	var total int64
+	if err := s.db.Model(&Endpoint{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	endpoints := []Endpoint{}
+	err := s.db.Order("key").Offset((page - 1) * pageSize).Limit(pageSize).Find(&endpoints).Error
+	return endpoints, total, err
+}
+
+func (s *sqlStore) ListResults(key string, days int) ([]Result, error) {
+	results := []Result{}
+	err := s.db.Where("key = ? AND day >= ?", key, sinceDay(days)).Order("day DESC").Find(&results).Error
+	return results, err
+}
+
+func (s *sqlStore) ListResponseTimes(key string, days int) ([]DayResponseTime, error) {
+	results, err := s.ListResults(key, days)
+	return toDayResponseTimes(results), err
+}
+
+func (s *sqlStore) ListChecks(key string, page, pageSize int) ([]Check, error) {
+	checks := []Check{}
+	err := s.db.Where(&Check{Key: key}).Order("checked_at DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&checks).Error
+	return checks, err
+}
+
+func (s *sqlStore) ListIncidents(key string, limit int) ([]Incident, error) {
+	incidents := []Incident{}
+	err := s.db.Where(&Incident{Key: key}).Order("opened_at DESC").Limit(limit).Find(&incidents).Error
+	return incidents, err
+}
+
+func (s *sqlStore) ListAllIncidents(limit int) ([]Incident, error) {
+	incidents := []Incident{}
+	err := s.db.Order("opened_at DESC").Limit(limit).Find(&incidents).Error
+	return incidents, err
+}
+
+func (s *sqlStore) Close() error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
@@ -1,64 +1,125 @@
 package storage
 
 import (
+	"github.com/glebarez/sqlite"
 	"github.com/samber/lo"
 	"github.com/serverless-aliyun/func-status/client/core"
 	"gorm.io/datatypes"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"math"
+	"strings"
 	"time"
 )
 
 // Endpoint from results
 type Endpoint struct {
-	gorm.Model
+	gorm.Model `json:"-"`
 
 	// Key of the endpoint. Auto generated.
-	Key string `gorm:"column:key;uniqueIndex:uidx_key"`
+	Key string `gorm:"column:key;uniqueIndex:uidx_key" json:"key"`
 
 	// Name of the endpoint. Can be anything.
-	Name string `gorm:"column:name"`
+	Name string `gorm:"column:name" json:"name"`
 
 	// URL to send the request to
-	URL string `gorm:"column:url"`
+	URL string `gorm:"column:url" json:"url"`
 
 	// Description of the endpoint
-	Desc string `gorm:"column:desc"`
+	Desc string `gorm:"column:desc" json:"desc,omitempty"`
 
 	// SLA of all results
-	SLA float64 `gorm:"column:sla"`
+	SLA float64 `gorm:"column:sla" json:"sla"`
 
 	// Status of latest (nodata, success, failure, partial)
-	Status string `gorm:"column:status"`
+	Status string `gorm:"column:status" json:"status"`
 }
 
 // Result from day result
 type Result struct {
-	gorm.Model
+	gorm.Model `json:"-"`
 
 	// Key of the endpoint. Reference of the Endpoint.
-	Key string `gorm:"column:key;uniqueIndex:uidx_key_day"`
+	Key string `gorm:"column:key;uniqueIndex:uidx_key_day" json:"key"`
 
 	// Day of check health
-	Day string `gorm:"column:day;uniqueIndex:uidx_key_day"`
+	Day string `gorm:"column:day;uniqueIndex:uidx_key_day" json:"day"`
 
 	// SLA of result by day
-	SLA float64 `gorm:"column:sla"`
+	SLA float64 `gorm:"column:sla" json:"sla"`
 
 	// Status of result by day (nodata, success, failure, partial)
-	Status string `gorm:"column:status"`
+	Status string `gorm:"column:status" json:"status"`
 
-	// Logs of the Endpoint's conditions
-	Logs datatypes.JSONSlice[ConditionLog] `gorm:"column:logs"`
+	// Checks is the number of checks of the day
+	Checks int `gorm:"column:checks" json:"checks"`
+
+	// SuccessfulChecks is the number of successful checks of the day
+	SuccessfulChecks int `gorm:"column:successful_checks" json:"successfulChecks"`
+
+	// Conditions is the number of conditions evaluated during the day
+	Conditions int `gorm:"column:conditions" json:"conditions"`
+
+	// SuccessfulConditions is the number of conditions met during the day
+	SuccessfulConditions int `gorm:"column:successful_conditions" json:"successfulConditions"`
+
+	// Monitored is the number of minutes from the first check of the day, or from the start of the day if the endpoint
+	// was already checked before, until the end of the day, or until the last aggregation if the day isn't over
+	Monitored float64 `gorm:"column:monitored" json:"monitored"`
+
+	// Downtime is the number of minutes the endpoint was down during Monitored, each failed check lasting until the
+	// check following it
+	Downtime float64 `gorm:"column:downtime" json:"downtime"`
+
+	// ResponseTime is the distribution of the response times of the day
+	ResponseTime ResponseTime `gorm:"embedded;embeddedPrefix:response_time_" json:"responseTime"`
 }
 
-type ConditionLog struct {
-	// Time of check health
-	Time string `json:"time"`
+// Check is the raw result of a single evaluation of an endpoint.
+//
+// Checks are hard deleted once they are older than the retention, hence the lack of gorm.Model.
+type Check struct {
+	ID uint `gorm:"primarykey" json:"-"`
+
+	// Key of the endpoint. Reference of the Endpoint.
+	Key string `gorm:"column:key;index:idx_check_key_checked_at" json:"key"`
+
+	// CheckedAt is the time the check was performed
+	CheckedAt time.Time `gorm:"column:checked_at;index:idx_check_key_checked_at" json:"checkedAt"`
+
+	// Duration of the request, in milliseconds
+	Duration int64 `gorm:"column:duration" json:"duration"`
+
+	// HTTPStatus is the HTTP response status code
+	HTTPStatus int `gorm:"column:http_status" json:"status"`
+
+	// Success whether all the conditions were met
+	Success bool `gorm:"column:success" json:"success"`
+
+	// Errors encountered during the check
+	Errors datatypes.JSONSlice[string] `gorm:"column:errors" json:"errors,omitempty"`
 
 	// Conditions result of the Endpoint's conditions
-	Conditions []ConditionResult `json:"conditions"`
+	Conditions datatypes.JSONSlice[ConditionResult] `gorm:"column:conditions" json:"conditions"`
+}
+
+// Incident from consecutive failed results
+type Incident struct {
+	gorm.Model `json:"-"`
+
+	// Key of the endpoint. Reference of the Endpoint.
+	Key string `gorm:"column:key;index:idx_incident_key" json:"key"`
+
+	// OpenedAt is the time of the first failed result
+	OpenedAt time.Time `gorm:"column:opened_at" json:"openedAt"`
+
+	// ResolvedAt is the time of the first successful result after the failure, nil while the incident is ongoing
+	ResolvedAt *time.Time `gorm:"column:resolved_at" json:"resolvedAt,omitempty"`
+
+	// Conditions that failed when the incident was opened
+	Conditions datatypes.JSONSlice[string] `gorm:"column:conditions" json:"conditions"`
+
+	// Errors encountered while the incident was ongoing
+	Errors datatypes.JSONSlice[string] `gorm:"column:errors" json:"errors,omitempty"`
 }
 
 type ConditionResult struct {
@@ -76,8 +137,6 @@ const (
 	StatusPartial = "partial"
 )
 
-var conn *gorm.DB
-
 func (Endpoint) TableName() string {
 	return "endpoint"
 }
@@ -86,36 +145,86 @@ func (Result) TableName() string {
 	return "endpoint_result"
 }
 
-func ConnectToDB(dsn string) error {
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
-	if err != nil {
-		return err
-	}
-	err = db.AutoMigrate(&Endpoint{}, &Result{})
-	if err != nil {
-		return err
+func (Check) TableName() string {
+	return "endpoint_check"
+}
+
+func (Incident) TableName() string {
+	return "endpoint_incident"
+}
+
+// Store is the interface that each storage backend should implement
+type Store interface {
+	// SaveResult stores the result as a check, refreshes the aggregates and the SLA of its day
+	// and deletes the checks and daily results older than maxDays
+	SaveResult(key string, result *core.Result, maxDays int, mode core.SLAMode) error
+
+	// SaveEndpoint creates or updates the endpoint along with its status and its SLA computed with the endpoint's SLA mode
+	SaveEndpoint(endpoint *core.Endpoint) error
+
+	// SaveIncident opens an incident on the first failed result and resolves it on the first successful one,
+	// and deletes the incidents resolved more than maxDays ago
+	SaveIncident(key string, result *core.Result, maxDays int) error
+
+	// ListEndpoints returns a page of endpoints ordered by key, as well as the total number of endpoints
+	ListEndpoints(page, pageSize int) ([]Endpoint, int64, error)
+
+	// ListResults returns the daily results of an endpoint for the last given number of days, most recent first
+	ListResults(key string, days int) ([]Result, error)
+
+	// ListResponseTimes returns the daily response times of an endpoint for the last given number of days, most recent first
+	ListResponseTimes(key string, days int) ([]DayResponseTime, error)
+
+	// ListChecks returns a page of the latest checks of an endpoint, most recent first
+	ListChecks(key string, page, pageSize int) ([]Check, error)
+
+	// ListIncidents returns the latest incidents of an endpoint, most recent first
+	ListIncidents(key string, limit int) ([]Incident, error)
+
+	// ListAllIncidents returns the latest incidents across all endpoints, most recent first
+	ListAllIncidents(limit int) ([]Incident, error)
+
+	// Close the connection to the storage
+	Close() error
+}
+
+const (
+	// MemorySchemePrefix is the DSN prefix selecting the in-memory store
+	MemorySchemePrefix = "memory://"
+
+	// SQLiteSchemePrefix is the DSN prefix selecting the SQLite store, followed by the path of the database file
+	SQLiteSchemePrefix = "sqlite://"
+)
+
+// NewStore creates the Store matching the scheme of the DSN.
+//
+// DSNs starting with memory:// and sqlite:// respectively select the in-memory and the SQLite stores,
+// any other DSN is handed to the Postgres driver.
+func NewStore(dsn string) (Store, error) {
+	switch {
+	case strings.HasPrefix(dsn, MemorySchemePrefix):
+		return NewMemoryStore(), nil
+	case strings.HasPrefix(dsn, SQLiteSchemePrefix):
+		// SQLite only supports a single writer at a time
+		return newSQLStore(sqlite.Open(strings.TrimPrefix(dsn, SQLiteSchemePrefix)), 1)
+	default:
+		return newSQLStore(postgres.Open(dsn), 0)
 	}
-	conn = db
-	return err
-}
-
-func SaveResult(key string, result *core.Result, maxDays int) {
-	// 删除历史数据
-	deleteDate := time.Now().AddDate(0, 0, -maxDays)
-	conn.Where("key = ? AND created_at < ?", key, deleteDate).Delete(&Result{})
-	// 查询当天数据
-	day := time.Now().Format("2006-01-02")
-	dayResult := &Result{
-		Key:    key,
-		Day:    day,
-		SLA:    0,
-		Status: StatusNoData,
-		Logs:   nil,
+}
+
+// newCheck creates the check of an endpoint from the result of its evaluation
+func newCheck(key string, result *core.Result) *Check {
+	checkedAt := result.Timestamp
+	if checkedAt.IsZero() {
+		checkedAt = time.Now()
 	}
-	conn.Where(&Result{Key: key, Day: day}).First(dayResult)
-	// 更新当天数据
-	nowResult := ConditionLog{
-		Time: time.Now().Format("15:04:05"),
+	return &Check{
+		Key:        key,
+		CheckedAt:  checkedAt,
+		Duration:   result.Duration.Milliseconds(),
+		HTTPStatus: result.HTTPStatus,
+		Success:    result.Success,
+		Errors:     result.Errors,
 		Conditions: lo.Map(result.ConditionResults, func(item *core.ConditionResult, index int) ConditionResult {
 			return ConditionResult{
 				Condition: item.Condition,
@@ -123,82 +232,68 @@ func SaveResult(key string, result *core.Result, maxDays int) {
 			}
 		}),
 	}
-	dayResult.Logs = append([]ConditionLog{nowResult}, dayResult.Logs...)
-	dayResult.Logs = dayResult.Logs[:int(math.Min(10, float64(len(dayResult.Logs))))]
-	// 计算SLA
-	status, sla := calcDaySLA(dayResult.Logs)
-	dayResult.Status = status
-	dayResult.SLA = sla
-	conn.Save(dayResult)
-}
-
-func SaveEndpoint(e *core.Endpoint) {
-	endpoint := &Endpoint{
-		Key:    e.Key(),
-		Name:   e.Name,
-		URL:    e.URL,
-		Status: StatusNoData,
-		SLA:    0,
+}
+
+// dayBounds returns the start of the day of the given time and the start of the following day
+func dayBounds(t time.Time) (time.Time, time.Time) {
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return start, start.AddDate(0, 0, 1)
+}
+
+// aggregatedUntil returns the end of the period covered by the aggregation of a day, which is now if the day isn't over
+func aggregatedUntil(dayEnd time.Time) time.Time {
+	if now := time.Now(); now.Before(dayEnd) {
+		return now
 	}
+	return dayEnd
+}
+
+// updateEndpoint updates the stored endpoint from its configuration and the SLA of its results
+func updateEndpoint(endpoint *Endpoint, e *core.Endpoint, results []Result) {
+	endpoint.Key = e.Key()
+	endpoint.Name = e.Name
+	endpoint.URL = e.URL
 	if e.Version != "" {
 		endpoint.Desc = "Running Version: " + e.Version
 	}
-	conn.Where(&Endpoint{Key: e.Key()}).First(endpoint)
-	var results []Result
-	conn.Where(&Result{Key: e.Key()}).Find(&results)
-	status, sla := calcEndpointSLA(results)
-	endpoint.Status = status
-	endpoint.SLA = sla
-	conn.Save(endpoint)
-}
-
-// calcDaySLA 每日状态计算: 全部成功 success (sla: 100)/全部失败 failure (sla: 0)/部分成功失败 partial (sla: 失败 condition / condition 总数)
-func calcDaySLA(logs datatypes.JSONSlice[ConditionLog]) (status string, sla float64) {
-	total := 0
-	success := 0
-	for _, r := range logs {
-		for _, cr := range r.Conditions {
-			if cr.Success {
-				success += 1
-			}
-			total += 1
+	endpoint.Status, endpoint.SLA = calcEndpointSLA(results, e.SLAMode)
+}
+
+// updateIncident opens, updates or resolves the incident depending on the result
+// and returns whether the incident needs to be saved
+func updateIncident(incident *Incident, ongoing bool, key string, result *core.Result) bool {
+	if result.Success {
+		if ongoing {
+			incident.ResolvedAt = &result.Timestamp
 		}
+		return ongoing
 	}
-	if success == 0 {
-		status = StatusFailure
-		sla = 0
-	} else if success == total {
-		status = StatusSuccess
-		sla = 100
-	} else {
-		status = StatusPartial
-		sla = math.Round(float64(success) * 100 / float64(total))
-	}
-	return status, sla
-}
-
-func calcEndpointSLA(results []Result) (status string, sla float64) {
-	total := 0
-	success := 0
-	for _, r := range results {
-		for _, l := range r.Logs {
-			for _, cr := range l.Conditions {
-				if cr.Success {
-					success += 1
-				}
-				total += 1
+	if !ongoing {
+		*incident = Incident{Key: key, OpenedAt: result.Timestamp}
+		for _, conditionResult := range result.ConditionResults {
+			if !conditionResult.Success {
+				incident.Conditions = append(incident.Conditions, conditionResult.Condition)
 			}
 		}
 	}
-	if success == 0 {
-		status = StatusFailure
-		sla = 0
-	} else if success == total {
-		status = StatusSuccess
-		sla = 100
-	} else {
-		status = StatusPartial
-		sla = math.Round(float64(success) * 100 / float64(total))
+	incident.Errors = lo.Uniq(append(incident.Errors, result.Errors...))
+	return true
+}
+
+// sinceDay returns the first day included in a range of the last given number of days
+func sinceDay(days int) string {
+	return time.Now().AddDate(0, 0, -days+1).Format("2006-01-02")
+}
+
+// paginate returns the given page of a slice, or an empty slice if the page is out of range
+func paginate[T any](items []T, page, pageSize int) []T {
+	start := (page - 1) * pageSize
+	if page < 1 || pageSize < 1 || start < 0 || start >= len(items) {
+		return []T{}
+	}
+	end := len(items)
+	if pageSize < end-start {
+		end = start + pageSize
 	}
-	return status, sla
+	return items[start:end]
 }
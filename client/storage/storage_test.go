@@ -0,0 +1,330 @@
+package storage
+
+import (
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/serverless-aliyun/func-status/client/core"
+	"gorm.io/gorm"
+)
+
+func newTestStores(t *testing.T) map[string]Store {
+	t.Helper()
+	sqliteStore, err := NewStore(SQLiteSchemePrefix + filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal("failed to create sqlite store:", err)
+	}
+	memoryStore, err := NewStore(MemorySchemePrefix)
+	if err != nil {
+		t.Fatal("failed to create memory store:", err)
+	}
+	stores := map[string]Store{"sqlite": sqliteStore, "memory": memoryStore}
+	t.Cleanup(func() {
+		for _, store := range stores {
+			_ = store.Close()
+		}
+	})
+	return stores
+}
+
+func newTestResult(success bool, timestamp time.Time, errors ...string) *core.Result {
+	return &core.Result{
+		Success:          success,
+		Timestamp:        timestamp,
+		Errors:           errors,
+		ConditionResults: []*core.ConditionResult{{Condition: "[STATUS] == 200", Success: success}},
+	}
+}
+
+func TestNewStore(t *testing.T) {
+	if store, err := NewStore("memory://"); err != nil {
+		t.Error("expected no error, got", err)
+	} else if _, ok := store.(*memoryStore); !ok {
+		t.Errorf("expected memory store, got %T", store)
+	}
+	if store, err := NewStore("sqlite://" + filepath.Join(t.TempDir(), "test.db")); err != nil {
+		t.Error("expected no error, got", err)
+	} else if _, ok := store.(*sqlStore); !ok {
+		t.Errorf("expected sql store, got %T", store)
+	}
+}
+
+func TestStore_SaveResultAndEndpoint(t *testing.T) {
+	for name, store := range newTestStores(t) {
+		t.Run(name, func(t *testing.T) {
+			endpoint := &core.Endpoint{Name: "website", URL: "https://example.org", Version: "1.2.3"}
+			for _, success := range []bool{true, true, false, true} {
+				if err := store.SaveResult(endpoint.Key(), newTestResult(success, time.Now()), 30, core.SLAModeCondition); err != nil {
+					t.Fatal("expected no error, got", err)
+				}
+			}
+			if err := store.SaveEndpoint(endpoint); err != nil {
+				t.Fatal("expected no error, got", err)
+			}
+			results, err := store.ListResults(endpoint.Key(), 7)
+			if err != nil {
+				t.Fatal("expected no error, got", err)
+			}
+			if len(results) != 1 {
+				t.Fatalf("expected 1 daily result, got %d", len(results))
+			}
+			if results[0].Status != StatusPartial || results[0].SLA != 75 || results[0].Checks != 4 || results[0].SuccessfulChecks != 3 {
+				t.Errorf("unexpected daily result %+v", results[0])
+			}
+			endpoints, total, err := store.ListEndpoints(1, 10)
+			if err != nil {
+				t.Fatal("expected no error, got", err)
+			}
+			if total != 1 || len(endpoints) != 1 {
+				t.Fatalf("expected 1 endpoint, got %d (total %d)", len(endpoints), total)
+			}
+			if endpoints[0].Key != "website" || endpoints[0].SLA != 75 || endpoints[0].Desc != "Running Version: 1.2.3" {
+				t.Errorf("unexpected endpoint %+v", endpoints[0])
+			}
+		})
+	}
+}
+
+func TestSQLStore_SaveEndpointWithLookupError(t *testing.T) {
+	store, err := NewStore(SQLiteSchemePrefix + filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal("failed to create sqlite store:", err)
+	}
+	defer store.Close()
+	db := store.(*sqlStore).db
+	// Only the lookup of the endpoint fails, so that its error isn't reported by the queries that follow it
+	err = db.Callback().Query().Before("gorm:query").Register("test:fail_endpoint_lookup", func(tx *gorm.DB) {
+		if tx.Statement.Table == "endpoint" {
+			_ = tx.AddError(errors.New("lookup failed"))
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveEndpoint(&core.Endpoint{Name: "website", URL: "https://example.org"}); err == nil || err.Error() != "lookup failed" {
+		t.Errorf("expected the error of the lookup of the endpoint, got %v", err)
+	}
+}
+
+func TestStore_SaveResultKeepsEveryCheck(t *testing.T) {
+	for name, store := range newTestStores(t) {
+		t.Run(name, func(t *testing.T) {
+			// An outage early in the day must still count once more than 10 checks were made
+			start := time.Now().Truncate(time.Second)
+			for i := 0; i < 20; i++ {
+				result := newTestResult(i >= 5, start.Add(time.Duration(i)*time.Millisecond), "timeout")
+				result.Duration = time.Duration(i) * time.Millisecond
+				result.HTTPStatus = 200
+				if err := store.SaveResult("website", result, 30, core.SLAModeCondition); err != nil {
+					t.Fatal("expected no error, got", err)
+				}
+			}
+			results, err := store.ListResults("website", 1)
+			if err != nil {
+				t.Fatal("expected no error, got", err)
+			}
+			if len(results) != 1 || results[0].Checks != 20 || results[0].SLA != 75 {
+				t.Errorf("expected the day to aggregate all 20 checks, got %+v", results)
+			}
+			responseTimes, err := store.ListResponseTimes("website", 1)
+			if err != nil {
+				t.Fatal("expected no error, got", err)
+			}
+			expectedResponseTime := ResponseTime{Min: 0, Avg: 10, P50: 9, P95: 18, P99: 19, Max: 19}
+			if len(responseTimes) != 1 || responseTimes[0].ResponseTime != expectedResponseTime {
+				t.Errorf("expected response times %+v, got %+v", expectedResponseTime, responseTimes)
+			}
+			checks, err := store.ListChecks("website", 1, 3)
+			if err != nil {
+				t.Fatal("expected no error, got", err)
+			}
+			if len(checks) != 3 || checks[0].Duration != 19 || !checks[0].Success || checks[0].HTTPStatus != 200 || len(checks[0].Conditions) != 1 {
+				t.Errorf("expected the 3 latest checks, most recent first, got %+v", checks)
+			}
+			if checks, _ = store.ListChecks("website", 7, 3); len(checks) != 2 || checks[1].Success || checks[1].Errors[0] != "timeout" {
+				t.Errorf("expected the 2 oldest checks on the last page, got %+v", checks)
+			}
+		})
+	}
+}
+
+func TestStore_SaveResultWithRetention(t *testing.T) {
+	for name, store := range newTestStores(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := store.SaveResult("website", newTestResult(false, time.Now().AddDate(0, 0, -3)), 30, core.SLAModeCondition); err != nil {
+				t.Fatal("expected no error, got", err)
+			}
+			if err := store.SaveResult("website", newTestResult(true, time.Now()), 2, core.SLAModeCondition); err != nil {
+				t.Fatal("expected no error, got", err)
+			}
+			if checks, _ := store.ListChecks("website", 1, 10); len(checks) != 1 || !checks[0].Success {
+				t.Errorf("expected the check older than the retention to be deleted, got %+v", checks)
+			}
+		})
+	}
+}
+
+func TestStore_SaveResultWithOutageAcrossMidnight(t *testing.T) {
+	for name, store := range newTestStores(t) {
+		t.Run(name, func(t *testing.T) {
+			twoDaysAgo := time.Now().AddDate(0, 0, -2)
+			midnight := time.Date(twoDaysAgo.Year(), twoDaysAgo.Month(), twoDaysAgo.Day()+1, 0, 0, 0, 0, time.Local)
+			for _, result := range []*core.Result{
+				newTestResult(true, midnight.Add(-2*time.Hour)),
+				newTestResult(false, midnight.Add(-time.Hour)),
+				newTestResult(true, midnight.Add(time.Hour)),
+				newTestResult(true, midnight.Add(2*time.Hour)),
+			} {
+				if err := store.SaveResult("website", result, 30, core.SLAModeTime); err != nil {
+					t.Fatal("expected no error, got", err)
+				}
+			}
+			results, err := store.ListResults("website", 3)
+			if err != nil {
+				t.Fatal("expected no error, got", err)
+			}
+			if len(results) != 2 {
+				t.Fatalf("expected 2 daily results, got %+v", results)
+			}
+			if after := results[0]; after.Downtime != 60 || after.Monitored != 24*60 {
+				t.Errorf("expected the outage to last until the first check after midnight, got %+v", after)
+			}
+			if before := results[1]; before.Downtime != 60 || before.Monitored != 120 {
+				t.Errorf("expected the outage to last until midnight, got %+v", before)
+			}
+		})
+	}
+}
+
+func TestStore_SaveIncident(t *testing.T) {
+	for name, store := range newTestStores(t) {
+		t.Run(name, func(t *testing.T) {
+			start := time.Now().Add(-time.Hour).Truncate(time.Second)
+			scenarios := []*core.Result{
+				newTestResult(true, start),
+				newTestResult(false, start.Add(time.Minute), "timeout"),
+				newTestResult(false, start.Add(2*time.Minute), "timeout", "connection refused"),
+				newTestResult(true, start.Add(3*time.Minute)),
+				newTestResult(false, start.Add(4*time.Minute)),
+			}
+			for _, result := range scenarios {
+				if err := store.SaveIncident("website", result, 30); err != nil {
+					t.Fatal("expected no error, got", err)
+				}
+			}
+			if err := store.SaveIncident("other", newTestResult(false, start.Add(5*time.Minute)), 30); err != nil {
+				t.Fatal("expected no error, got", err)
+			}
+			incidents, err := store.ListIncidents("website", 10)
+			if err != nil {
+				t.Fatal("expected no error, got", err)
+			}
+			if len(incidents) != 2 {
+				t.Fatalf("expected 2 incidents, got %d", len(incidents))
+			}
+			if incidents[0].ResolvedAt != nil || !incidents[0].OpenedAt.Equal(start.Add(4*time.Minute)) {
+				t.Errorf("expected the latest incident to be ongoing, got %+v", incidents[0])
+			}
+			resolved := incidents[1]
+			if !resolved.OpenedAt.Equal(start.Add(time.Minute)) || resolved.ResolvedAt == nil || !resolved.ResolvedAt.Equal(start.Add(3*time.Minute)) {
+				t.Errorf("expected the first incident to be resolved after 2 minutes, got %+v", resolved)
+			}
+			if len(resolved.Errors) != 2 || len(resolved.Conditions) != 1 || resolved.Conditions[0] != "[STATUS] == 200" {
+				t.Errorf("unexpected errors or conditions %+v", resolved)
+			}
+			all, err := store.ListAllIncidents(2)
+			if err != nil {
+				t.Fatal("expected no error, got", err)
+			}
+			if len(all) != 2 || all[0].Key != "other" {
+				t.Errorf("expected the 2 latest incidents across all endpoints, got %+v", all)
+			}
+		})
+	}
+}
+
+func TestStore_SaveIncidentWithRetention(t *testing.T) {
+	for name, store := range newTestStores(t) {
+		t.Run(name, func(t *testing.T) {
+			longAgo := time.Now().AddDate(0, 0, -10).Truncate(time.Second)
+			scenarios := []*core.Result{
+				newTestResult(false, longAgo),
+				newTestResult(true, longAgo.Add(time.Minute)),
+				newTestResult(false, longAgo.Add(2*time.Minute)),
+			}
+			for _, result := range scenarios {
+				if err := store.SaveIncident("website", result, 30); err != nil {
+					t.Fatal("expected no error, got", err)
+				}
+			}
+			if err := store.SaveIncident("other", newTestResult(false, longAgo), 30); err != nil {
+				t.Fatal("expected no error, got", err)
+			}
+			if err := store.SaveIncident("other", newTestResult(true, longAgo.Add(time.Minute)), 30); err != nil {
+				t.Fatal("expected no error, got", err)
+			}
+			if err := store.SaveIncident("website", newTestResult(false, time.Now()), 5); err != nil {
+				t.Fatal("expected no error, got", err)
+			}
+			incidents, err := store.ListIncidents("website", 10)
+			if err != nil {
+				t.Fatal("expected no error, got", err)
+			}
+			if len(incidents) != 1 || incidents[0].ResolvedAt != nil || !incidents[0].OpenedAt.Equal(longAgo.Add(2*time.Minute)) {
+				t.Errorf("expected only the ongoing incident to be kept, got %+v", incidents)
+			}
+			if others, _ := store.ListIncidents("other", 10); len(others) != 1 {
+				t.Errorf("expected the incidents of other endpoints to be kept, got %+v", others)
+			}
+		})
+	}
+}
+
+func TestUpdateIncident(t *testing.T) {
+	now := time.Now()
+	incident := &Incident{}
+	if updateIncident(incident, false, "website", newTestResult(true, now)) {
+		t.Error("a successful result without an ongoing incident shouldn't need to be saved")
+	}
+	if !updateIncident(incident, false, "website", newTestResult(false, now, "timeout")) {
+		t.Error("a failed result should open an incident")
+	}
+	if incident.Key != "website" || !incident.OpenedAt.Equal(now) || len(incident.Conditions) != 1 || incident.ResolvedAt != nil {
+		t.Errorf("unexpected opened incident %+v", incident)
+	}
+	if !updateIncident(incident, true, "website", newTestResult(false, now.Add(time.Minute), "timeout", "connection refused")) {
+		t.Error("a failed result should update the ongoing incident")
+	}
+	if len(incident.Errors) != 2 || !incident.OpenedAt.Equal(now) {
+		t.Errorf("expected the errors of the ongoing incident to be merged, got %+v", incident)
+	}
+	resolvedAt := now.Add(2 * time.Minute)
+	if !updateIncident(incident, true, "website", newTestResult(true, resolvedAt)) {
+		t.Error("a successful result should resolve the ongoing incident")
+	}
+	if incident.ResolvedAt == nil || !incident.ResolvedAt.Equal(resolvedAt) {
+		t.Errorf("expected the incident to be resolved at %s, got %v", resolvedAt, incident.ResolvedAt)
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	if page := paginate(items, 1, 2); len(page) != 2 || page[0] != 1 {
+		t.Errorf("unexpected first page %v", page)
+	}
+	if page := paginate(items, 3, 2); len(page) != 1 || page[0] != 5 {
+		t.Errorf("unexpected last page %v", page)
+	}
+	if page := paginate(items, 4, 2); len(page) != 0 {
+		t.Errorf("expected empty page, got %v", page)
+	}
+	if page := paginate(items, math.MaxInt/2+2, 2); len(page) != 0 {
+		t.Errorf("expected empty page when the offset overflows, got %v", page)
+	}
+	if page := paginate(items, 1, math.MaxInt); len(page) != 5 {
+		t.Errorf("expected every item when the page size exceeds the number of items, got %v", page)
+	}
+}
@@ -0,0 +1,86 @@
+package util
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// ConnectionTimeout is the maximum duration allowed to establish a TCP, UDP or TLS connection
+const ConnectionTimeout = 10 * time.Second
+
+// ErrNoPeerCertificate is the error returned when a TLS handshake succeeded without any certificate being presented
+var ErrNoPeerCertificate = errors.New("no certificate was presented by the server")
+
+// CanCreateTCPConnection checks whether a connection can be established with a TCP endpoint
+func CanCreateTCPConnection(address string) bool {
+	conn, err := net.DialTimeout("tcp", address, ConnectionTimeout)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
+
+// CanCreateUDPConnection checks whether a connection can be established with a UDP endpoint
+//
+// Note that UDP is connectionless, so this only validates that the address resolves and that a socket can be opened.
+func CanCreateUDPConnection(address string) bool {
+	conn, err := net.DialTimeout("udp", address, ConnectionTimeout)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
+
+// CanPerformTLS checks whether a TLS handshake can be performed with the endpoint and returns the leaf certificate.
+// If tlsConfig is nil, the system's root CAs are used to verify the certificate.
+func CanPerformTLS(address string, tlsConfig *tls.Config) (bool, *x509.Certificate, error) {
+	connection, err := tls.DialWithDialer(&net.Dialer{Timeout: ConnectionTimeout}, "tcp", address, tlsConfig)
+	if err != nil {
+		return false, nil, err
+	}
+	defer connection.Close()
+	certificates := connection.ConnectionState().PeerCertificates
+	if len(certificates) == 0 {
+		return true, nil, ErrNoPeerCertificate
+	}
+	return true, certificates[0], nil
+}
+
+// QueryWebSocket opens a WebSocket connection with the given headers, sends the body and returns the first message
+// received in reply.
+func QueryWebSocket(address, body string, headers map[string]string) (bool, []byte, error) {
+	// The origin is only used for the handshake, so it is derived from the address itself
+	origin := "http" + strings.TrimPrefix(address, "ws")
+	config, err := websocket.NewConfig(address, origin)
+	if err != nil {
+		return false, nil, err
+	}
+	config.Dialer = &net.Dialer{Timeout: ConnectionTimeout}
+	config.Header = http.Header{}
+	for k, v := range headers {
+		config.Header.Set(k, v)
+	}
+	ws, err := websocket.DialConfig(config)
+	if err != nil {
+		return false, nil, err
+	}
+	defer ws.Close()
+	_ = ws.SetDeadline(time.Now().Add(ConnectionTimeout))
+	if err := websocket.Message.Send(ws, body); err != nil {
+		return true, nil, err
+	}
+	var reply []byte
+	if err := websocket.Message.Receive(ws, &reply); err != nil {
+		return true, nil, err
+	}
+	return true, reply, nil
+}
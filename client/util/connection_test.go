@@ -0,0 +1,84 @@
+package util
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/websocket"
+)
+
+func TestCanCreateTCPConnection(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	if !CanCreateTCPConnection(address) {
+		t.Error("expected to be able to connect to", address)
+	}
+	listener.Close()
+	if CanCreateTCPConnection(address) {
+		t.Error("expected not to be able to connect to a closed listener")
+	}
+}
+
+func TestCanCreateUDPConnection(t *testing.T) {
+	if !CanCreateUDPConnection("127.0.0.1:53") {
+		t.Error("expected to be able to open an UDP socket")
+	}
+	if CanCreateUDPConnection("127.0.0.1") {
+		t.Error("expected an address without port to fail")
+	}
+}
+
+func TestCanPerformTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	address := strings.TrimPrefix(server.URL, "https://")
+	if connected, _, err := CanPerformTLS(address, nil); connected || err == nil {
+		t.Error("expected the self-signed certificate to be rejected")
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	connected, certificate, err := CanPerformTLS(address, &tls.Config{RootCAs: pool, ServerName: "example.com"})
+	if err != nil {
+		t.Fatal("expected no error, got", err.Error())
+	}
+	if !connected {
+		t.Error("expected to be connected")
+	}
+	if certificate == nil || !certificate.NotAfter.Equal(server.Certificate().NotAfter) {
+		t.Error("expected the server's certificate to be returned")
+	}
+}
+
+func TestQueryWebSocket(t *testing.T) {
+	server := httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		var message string
+		if err := websocket.Message.Receive(ws, &message); err != nil {
+			return
+		}
+		_ = websocket.Message.Send(ws, `{"echo":"`+message+`","token":"`+ws.Request().Header.Get("Authorization")+`"}`)
+	}))
+	defer server.Close()
+	address := "ws" + strings.TrimPrefix(server.URL, "http")
+	connected, body, err := QueryWebSocket(address, "ping", map[string]string{"Authorization": "Bearer secret"})
+	if err != nil {
+		t.Fatal("expected no error, got", err.Error())
+	}
+	if !connected {
+		t.Error("expected to be connected")
+	}
+	if expected := `{"echo":"ping","token":"Bearer secret"}`; string(body) != expected {
+		t.Errorf("expected body %s, got %s", expected, body)
+	}
+	server.Close()
+	if connected, _, err := QueryWebSocket(address, "ping", nil); connected || err == nil {
+		t.Error("expected not to be able to connect to a closed server")
+	}
+}
@@ -3,12 +3,17 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/serverless-aliyun/func-status/client/alerting"
+	"github.com/serverless-aliyun/func-status/client/api"
 	"github.com/serverless-aliyun/func-status/client/config"
+	"github.com/serverless-aliyun/func-status/client/core"
+	"github.com/serverless-aliyun/func-status/client/metrics"
+	"github.com/serverless-aliyun/func-status/client/scheduler"
 	"github.com/serverless-aliyun/func-status/client/storage"
 	"log"
 	"net/http"
 	"os"
-	"time"
 )
 
 func main() {
@@ -17,19 +22,25 @@ func main() {
 		log.Panicln(err)
 		return
 	}
-	err = storage.ConnectToDB(cfg.DSN)
+	store, err := storage.NewStore(cfg.DSN)
 	if err != nil {
+		log.Panicln(err)
 		return
 	}
+	runner := scheduler.New(cfg.Scheduler)
+	go runner.Start(cfg.Endpoints, handleResult(cfg, store), nil)
 	http.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
 		_, _ = fmt.Fprintf(w, "pong")
 	})
 
 	http.HandleFunc("/check", func(w http.ResponseWriter, r *http.Request) {
-		check(cfg)
+		check(cfg, runner, store)
 		_, _ = fmt.Fprintf(w, "done")
 	})
 
+	http.Handle("/api/", api.NewHandler(store))
+	http.Handle("/metrics", promhttp.Handler())
+
 	port := os.Getenv("FC_SERVER_PORT")
 	if port == "" {
 		port = "9000"
@@ -39,21 +50,33 @@ func main() {
 	log.Fatal(http.ListenAndServe(":"+port, nil))
 }
 
-func check(cfg *config.Config) {
-	for _, endpoint := range cfg.Endpoints {
-		if endpoint.IsEnabled() {
-			time.Sleep(777 * time.Millisecond)
-			result := endpoint.EvaluateHealth()
-
-			// save result to db
-			storage.SaveResult(endpoint.Key(), result, cfg.MaxDays)
-			// save endpoint to db
-			storage.SaveEndpoint(endpoint)
-
-			if cfg.Debug {
-				rb, _ := json.Marshal(result)
-				fmt.Println(string(rb))
-			}
+func check(cfg *config.Config, runner *scheduler.Scheduler, store storage.Store) {
+	report := runner.Run(cfg.Endpoints, handleResult(cfg, store))
+	log.Printf("Checked %d endpoints (%d failed, %d not due) in %s", report.Evaluated, report.Failed, report.Skipped, report.Duration)
+}
+
+func handleResult(cfg *config.Config, store storage.Store) scheduler.Handler {
+	return func(endpoint *core.Endpoint, result *core.Result) {
+		// save result to db
+		if err := store.SaveResult(endpoint.Key(), result, cfg.MaxDays, endpoint.SLAMode); err != nil {
+			log.Printf("Failed to save result of endpoint %s: %s", endpoint.Key(), err.Error())
+		}
+		// save endpoint to db
+		if err := store.SaveEndpoint(endpoint); err != nil {
+			log.Printf("Failed to save endpoint %s: %s", endpoint.Key(), err.Error())
+		}
+		// open or resolve incident
+		if err := store.SaveIncident(endpoint.Key(), result, cfg.MaxDays); err != nil {
+			log.Printf("Failed to save incident of endpoint %s: %s", endpoint.Key(), err.Error())
+		}
+		// publish prometheus metrics
+		metrics.PublishMetricsForEndpoint(endpoint, result)
+		// send or resolve alerts
+		alerting.HandleAlerting(endpoint, result, cfg.Alerting)
+
+		if cfg.Debug {
+			rb, _ := json.Marshal(result)
+			fmt.Println(string(rb))
 		}
 	}
 }